@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/daemon"
+)
+
+const cacheUsage = "usage: shinkansen cache [info | migrate | reset [-force]]"
+
+// runCache implements `shinkansen cache`: inspect, migrate or delete the
+// local cache database.
+func runCache(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(cacheUsage)
+	}
+	switch args[0] {
+	case "info":
+		return cacheInfo()
+	case "migrate":
+		return cacheMigrate()
+	case "reset":
+		return cacheReset(args[1:])
+	}
+	return fmt.Errorf(cacheUsage)
+}
+
+func cacheInfo() error {
+	path, err := cache.Path()
+	if err != nil {
+		return err
+	}
+	store, err := cache.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	info, err := store.Info()
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, p := range []string{path, path + "-wal"} {
+		if st, err := os.Stat(p); err == nil {
+			size += st.Size()
+		}
+	}
+	fmt.Printf("Path:     %s (%.1f MB)\n", path, float64(size)/(1<<20))
+	fmt.Printf("Schema:   version %d, this build writes %d\n", info.Version, cache.SchemaVersion())
+	if info.Version > cache.SchemaVersion() {
+		fmt.Println("          written by a newer shinkansen")
+		return nil
+	}
+	if info.Version < cache.SchemaVersion() {
+		fmt.Println("          'shinkansen cache migrate' (or any start) upgrades it")
+	}
+	fmt.Printf("Issues:   %d\n", info.Issues)
+	scopes := make([]string, 0, len(info.ByScope))
+	for scope := range info.ByScope {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		fmt.Printf("          %d in %s\n", info.ByScope[scope], scope)
+	}
+	fmt.Printf("Queued:   %d worklogs\n", info.PendingWorklogs)
+	fmt.Printf("Unread:   %d notifications\n", info.UnreadEvents)
+	if !info.LastSync.IsZero() {
+		fmt.Printf("Synced:   %s ago\n", time.Since(info.LastSync).Round(time.Second))
+	}
+	return nil
+}
+
+func cacheMigrate() error {
+	store, err := cache.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	from, to, err := store.Migrate()
+	if err != nil {
+		return err
+	}
+	if from == to {
+		fmt.Printf("Cache schema is up to date (version %d)\n", to)
+		return nil
+	}
+	fmt.Printf("Migrated cache schema from version %d to %d\n", from, to)
+	return nil
+}
+
+func cacheReset(args []string) error {
+	fs := flag.NewFlagSet("cache reset", flag.ExitOnError)
+	force := fs.Bool("force", false, "Reset even with worklogs waiting to be sent")
+	fs.Parse(args)
+
+	if c, err := daemon.Dial(); err == nil {
+		c.Close()
+		return fmt.Errorf("the sync daemon is running; stop it first")
+	}
+
+	if !*force {
+		if err := checkQueuedWorklogs(); err != nil {
+			return err
+		}
+	}
+
+	if err := cache.Reset(); err != nil {
+		return err
+	}
+	fmt.Println("Cache deleted; the next start or sync rebuilds it from Jira")
+	return nil
+}
+
+// checkQueuedWorklogs refuses a reset that would drop worklogs logged
+// offline, or a cache it can't read.
+func checkQueuedWorklogs() error {
+	store, err := cache.Open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	info, err := store.Info()
+	if err != nil {
+		return fmt.Errorf("read cache: %w; pass -force to delete it anyway", err)
+	}
+	if info.PendingWorklogs > 0 {
+		return fmt.Errorf("%d worklogs logged offline haven't been sent yet and would be lost; sync first, or pass -force", info.PendingWorklogs)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/daemon"
+)
+
+const daemonUsage = "usage: shinkansen daemon [run | status]"
+
+// runDaemon implements `shinkansen daemon`: run the sync daemon in the
+// foreground, or report on a running one.
+func runDaemon(args []string) error {
+	cmd := "run"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	switch cmd {
+	case "run":
+		cfg, client, store, err := openSession()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		path, _ := daemon.SocketPath()
+		for _, scope := range cfg.Scopes() {
+			fmt.Fprintf(os.Stderr, "Syncing %s every %ds\n", scope.Name, scope.Interval)
+		}
+		fmt.Fprintf(os.Stderr, "Listening on %s\n", path)
+		return daemon.NewServer(cfg, client, store).Run(ctx)
+
+	case "status":
+		c, err := daemon.Dial()
+		if err != nil {
+			return fmt.Errorf("no daemon running")
+		}
+		defer c.Close()
+		st, err := c.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("pid %d, scopes %s, %d subscribers\n", st.PID, strings.Join(st.Scopes, ", "), st.Subscribers)
+		switch {
+		case st.Syncing:
+			fmt.Println("Syncing now")
+		case !st.LastSync.IsZero():
+			fmt.Printf("Last sync %s ago\n", time.Since(st.LastSync).Round(time.Second))
+		}
+		if st.Last != nil && st.Last.Error != "" {
+			fmt.Printf("Last error: %s\n", st.Last.Error)
+		}
+		return nil
+	}
+	return fmt.Errorf(daemonUsage)
+}
+
+// runSync implements `shinkansen sync`, through the daemon when one runs.
+func runSync(args []string) error {
+	var result cache.SyncResult
+	if c, err := daemon.Dial(); err == nil {
+		defer c.Close()
+		rep, err := c.Sync()
+		if err != nil {
+			return err
+		}
+		result = rep.Result()
+	} else {
+		cfg, client, store, err := openSession()
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		result = cache.Sync(client, store, cfg.Scopes())
+	}
+	if result.Err != nil {
+		return result.Err
+	}
+	fmt.Printf("Synced %d issues in %dms", result.ItemsSynced, result.Duration.Milliseconds())
+	if result.Pruned+result.Rekeyed > 0 {
+		fmt.Printf(", removed %d and renamed %d stale issues", result.Pruned, result.Rekeyed)
+	}
+	if n := len(result.Events); n > 0 {
+		fmt.Printf(", %d new notifications", n)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// runFields implements `shinkansen fields`, listing the site's fields with
+// their IDs and schemas so custom fields can be mapped in the config.
+func runFields(args []string) error {
+	fs := flag.NewFlagSet("fields", flag.ExitOnError)
+	all := fs.Bool("all", false, "Include system fields, not just custom fields")
+	fs.Parse(args)
+	filter := strings.ToLower(strings.Join(fs.Args(), " "))
+
+	cfg, client, store, err := openSession()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	fields, err := client.GetFields()
+	if err != nil {
+		return err
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return strings.ToLower(fields[i].Name) < strings.ToLower(fields[j].Name)
+	})
+
+	mapped := make(map[string]string)
+	for name, id := range cfg.CustomFields {
+		mapped[id] = name
+	}
+
+	fmt.Printf("%-22s %-36s %-22s %s\n", "ID", "Name", "Type", "Mapped as")
+	for _, f := range fields {
+		if !f.Custom && !*all {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(f.Name), filter) && !strings.Contains(f.ID, filter) {
+			continue
+		}
+		typ := ""
+		if f.Schema != nil {
+			typ = f.Schema.Type
+			if f.Schema.Items != "" {
+				typ += "<" + f.Schema.Items + ">"
+			}
+		}
+		name := runewidth.FillRight(runewidth.Truncate(f.Name, 35, "..."), 36)
+		fmt.Printf("%-22s %s %-22s %s\n", f.ID, name, typ, mapped[f.ID])
+	}
+
+	fmt.Println()
+	fmt.Println(`Map fields in config.json, e.g. "custom_fields": {"story_points": "customfield_10016"}`)
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/config"
+)
+
+const issueUsage = "usage: shinkansen issue create [-template NAME] [-project KEY] [-set name=value]... [-summary TEXT] | templates"
+
+// setFlags collects repeated -set name=value flags.
+type setFlags map[string]string
+
+func (s setFlags) String() string { return "" }
+
+func (s setFlags) Set(v string) error {
+	name, value, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected name=value, got %q", v)
+	}
+	s[strings.TrimSpace(name)] = value
+	return nil
+}
+
+// runIssue implements `shinkansen issue`.
+func runIssue(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(issueUsage)
+	}
+	switch args[0] {
+	case "create":
+		return runIssueCreate(args[1:])
+	case "templates":
+		return runIssueTemplates()
+	}
+	return fmt.Errorf(issueUsage)
+}
+
+func runIssueCreate(args []string) error {
+	fs := flag.NewFlagSet("issue create", flag.ExitOnError)
+	templateName := fs.String("template", "", "Template name from ~/.config/shinkansen/templates")
+	project := fs.String("project", "", "Project key (default: template project, then default_project)")
+	summary := fs.String("summary", "", "Summary, when not using a template")
+	issueType := fs.String("type", "", "Issue type, overriding the template's")
+	values := setFlags{}
+	fs.Var(values, "set", "Placeholder value as name=value (repeatable)")
+	fs.Parse(args)
+
+	var t *config.Template
+	if *templateName != "" {
+		var err error
+		if t, err = config.LoadTemplate(*templateName); err != nil {
+			return err
+		}
+	} else {
+		if *summary == "" {
+			return fmt.Errorf("-template or -summary is required")
+		}
+		t = &config.Template{Summary: *summary}
+	}
+	if *issueType != "" {
+		t.Type = *issueType
+	}
+
+	cfg, client, store, err := openSession()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	projectKey := firstNonEmpty(*project, t.Project, cfg.DefaultProject)
+	if projectKey == "" {
+		return fmt.Errorf("no project: pass -project or set default_project")
+	}
+
+	// Ask for placeholders not given with -set
+	in := bufio.NewReader(os.Stdin)
+	for _, name := range t.Placeholders() {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		fmt.Printf("%s: ", name)
+		line, err := in.ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("no value for %s", name)
+		}
+		values[name] = strings.TrimRight(line, "\r\n")
+	}
+
+	issue, subtasks, err := cache.CreateFromTemplate(client, store, t, projectKey, values)
+	if issue != nil {
+		fmt.Printf("Created %s  %s\n", issue.Key, cfg.BrowseURL(issue.Key))
+		for _, key := range subtasks {
+			fmt.Printf("  subtask %s\n", key)
+		}
+	}
+	return err
+}
+
+func runIssueTemplates() error {
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		dir, _ := config.TemplateDir()
+		fmt.Printf("No templates in %s\n", dir)
+		return nil
+	}
+	for _, t := range templates {
+		line := fmt.Sprintf("%-16s %-10s %s", t.Name, t.Type, t.Summary)
+		if n := len(t.Subtasks); n > 0 {
+			line += fmt.Sprintf("  (+%d subtasks)", n)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
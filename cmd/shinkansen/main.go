@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"time"
+	_ "time/tzdata" // Jira profile timezones for sync, without relying on the system's zoneinfo
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/temujinlabs/shinkansen/internal/cache"
@@ -16,6 +18,19 @@ import (
 
 var version = "dev"
 
+// subcommands work against the configured Jira site and local cache.
+var subcommands = map[string]func(args []string) error{
+	"cache":     runCache,
+	"daemon":    runDaemon,
+	"fields":    runFields,
+	"issue":     runIssue,
+	"notify":    runNotify,
+	"sync":      runSync,
+	"timer":     runTimer,
+	"timesheet": runTimesheet,
+	"webhook":   runWebhook,
+}
+
 func main() {
 	loginCmd := flag.NewFlagSet("login", flag.ExitOnError)
 	oauthFlag := loginCmd.Bool("oauth", false, "Use OAuth 2.0 (3LO) instead of API token")
@@ -40,6 +55,16 @@ func main() {
 		return
 	}
 
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Only parse global flags when NOT in a subcommand
 	versionFlag := flag.Bool("version", false, "Print version")
 	flag.Parse()
@@ -49,18 +74,9 @@ func main() {
 		os.Exit(0)
 	}
 
-	cfg, err := config.Load()
-	if err != nil || cfg.JiraURL == "" {
-		fmt.Println("Not configured. Run 'shinkansen login' first.")
-		os.Exit(1)
-	}
-
-	// Use config-aware client (supports both API token and OAuth)
-	client := jira.NewClientFromConfig(cfg)
-
-	store, err := cache.NewStore()
+	cfg, client, store, err := openSession()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Cache error: %v\n", err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 	defer store.Close()
@@ -73,6 +89,28 @@ func main() {
 	}
 }
 
+// openSession loads the config, a Jira client and the local cache.
+func openSession() (*config.Config, *jira.Client, *cache.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if cfg.JiraURL == "" {
+		return nil, nil, nil, fmt.Errorf("Not configured. Run 'shinkansen login' first.")
+	}
+
+	// Use config-aware client (supports both API token and OAuth)
+	client := jira.NewClientFromConfig(cfg)
+
+	store, err := cache.NewStore()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Cache error: %v", err)
+	}
+	store.SetCustomFields(cfg.CustomFields)
+	store.SetSyncOverlap(time.Duration(cfg.SyncOverlapSeconds) * time.Second)
+	return cfg, client, store, nil
+}
+
 func openBrowser(url string) {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
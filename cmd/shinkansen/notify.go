@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/notify"
+)
+
+const notifyUsage = "usage: shinkansen notify test"
+
+// runNotify implements `shinkansen notify`.
+func runNotify(args []string) error {
+	if len(args) == 0 || args[0] != "test" {
+		return fmt.Errorf(notifyUsage)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := notify.New(cfg).Test(); err != nil {
+		return err
+	}
+	fmt.Println("Sent a test notification")
+	return nil
+}
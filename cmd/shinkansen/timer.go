@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+const timerUsage = "usage: shinkansen timer start KEY | stop [-m comment] [-discard] | pause | resume | status | queue [retry|drop ID]"
+
+// runTimer implements `shinkansen timer`, sharing the timer stored in the
+// cache with the TUI.
+func runTimer(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(timerUsage)
+	}
+	cfg, client, store, err := openSession()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	now := time.Now()
+	switch args[0] {
+	case "start":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: shinkansen timer start KEY")
+		}
+		if err := store.StartTimer(args[1], now); err != nil {
+			return err
+		}
+		fmt.Printf("Timer started on %s\n", args[1])
+
+	case "pause":
+		t, err := store.ActiveTimer()
+		if err != nil || t == nil {
+			return fmt.Errorf("no timer running")
+		}
+		if err := store.PauseTimer(now); err != nil {
+			return err
+		}
+		fmt.Printf("Timer on %s paused at %s\n", t.IssueKey, jira.FormatDuration(t.Elapsed(now)))
+
+	case "resume":
+		t, err := store.ActiveTimer()
+		if err != nil || t == nil {
+			return fmt.Errorf("no timer to resume")
+		}
+		if err := store.ResumeTimer(now); err != nil {
+			return err
+		}
+		fmt.Printf("Timer on %s resumed\n", t.IssueKey)
+
+	case "stop":
+		fs := flag.NewFlagSet("timer stop", flag.ExitOnError)
+		comment := fs.String("m", "", "Worklog comment")
+		discard := fs.Bool("discard", false, "Stop without logging work")
+		fs.Parse(args[1:])
+
+		t, err := store.ActiveTimer()
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return fmt.Errorf("no timer running")
+		}
+		if *discard {
+			if _, err := store.StopTimer(now); err != nil {
+				return err
+			}
+			fmt.Printf("Timer on %s discarded (%s)\n", t.IssueKey, jira.FormatDuration(t.Elapsed(now)))
+			return nil
+		}
+
+		// The timer is only removed once the work is logged or queued, so a
+		// rejected worklog doesn't lose the tracked time
+		increment := time.Duration(cfg.TimerRoundMinutes) * time.Minute
+		spent := jira.FormatDuration(cache.RoundDuration(t.Elapsed(now), increment))
+		in := jira.WorklogInput{TimeSpent: spent, Started: t.StartedAt, Comment: *comment}
+		err = client.LogWork(t.IssueKey, in)
+		if jira.IsOffline(err) {
+			if qerr := store.QueueWorklog(t.IssueKey, in); qerr != nil {
+				return fmt.Errorf("log work: %v (queueing failed: %v); timer on %s kept", err, qerr, t.IssueKey)
+			}
+			if _, err := store.StopTimer(now); err != nil {
+				return err
+			}
+			fmt.Printf("Offline: %s on %s queued until next sync\n", spent, t.IssueKey)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("log work: %w; timer on %s kept", err, t.IssueKey)
+		}
+		if _, err := store.StopTimer(now); err != nil {
+			return err
+		}
+		fmt.Printf("Logged %s on %s\n", spent, t.IssueKey)
+
+	case "status":
+		t, err := store.ActiveTimer()
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			fmt.Println("No timer running")
+		} else {
+			state := "running"
+			if !t.Running() {
+				state = "paused"
+			}
+			fmt.Printf("%s: %s (%s, started %s)\n", t.IssueKey, jira.FormatDuration(t.Elapsed(now)), state, t.StartedAt.Local().Format("2006-01-02 15:04"))
+		}
+		return printQueue(store)
+
+	case "queue":
+		return runTimerQueue(client, store, args[1:])
+
+	default:
+		return fmt.Errorf(timerUsage)
+	}
+	return nil
+}
+
+// printQueue lists queued worklogs with the IDs `timer queue` takes.
+func printQueue(store *cache.Store) error {
+	pending, err := store.PendingWorklogs()
+	if err != nil {
+		return err
+	}
+	for _, p := range pending {
+		line := fmt.Sprintf("Queued #%d: %s on %s (%s)", p.ID, p.Input.TimeSpent, p.IssueKey, p.Input.Started.Local().Format("2006-01-02 15:04"))
+		if p.LastError != "" {
+			line += " rejected: " + p.LastError
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// runTimerQueue implements `shinkansen timer queue`: list the queued
+// worklogs, send a rejected one again after fixing the cause (e.g. a
+// reopened issue or a granted permission), or drop one.
+func runTimerQueue(client *jira.Client, store *cache.Store, args []string) error {
+	if len(args) == 0 {
+		return printQueue(store)
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: shinkansen timer queue [retry|drop ID]")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid worklog ID %q", args[1])
+	}
+
+	switch args[0] {
+	case "retry":
+		if err := store.RetryWorklog(id); err != nil {
+			return err
+		}
+		sent, err := cache.FlushWorklogs(client, store)
+		if jira.IsOffline(err) {
+			fmt.Printf("Offline: #%d will be sent on the next sync\n", id)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("sent %d queued worklogs, then: %w", sent, err)
+		}
+		fmt.Printf("Sent %d queued worklogs\n", sent)
+		return printQueue(store)
+	case "drop":
+		if err := store.DropWorklog(id); err != nil {
+			return err
+		}
+		fmt.Printf("Dropped queued worklog #%d\n", id)
+	default:
+		return fmt.Errorf("usage: shinkansen timer queue [retry|drop ID]")
+	}
+	return nil
+}
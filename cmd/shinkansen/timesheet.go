@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// runTimesheet implements `shinkansen timesheet`, printing the current
+// user's logged time for a week as an issue × weekday table.
+func runTimesheet(args []string) error {
+	fs := flag.NewFlagSet("timesheet", flag.ExitOnError)
+	week := fs.String("week", "", `Week to show: a date within it (YYYY-MM-DD) or an offset like "-1" for last week (default: this week)`)
+	fs.Parse(args)
+
+	weekStart, err := parseWeek(*week, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, client, store, err := openSession()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	sheet, err := client.Timesheet(cfg.AccountID, weekStart)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Week of %s\n\n", weekStart.Format("Mon Jan 2, 2006"))
+	fmt.Printf("%-40s", "")
+	for d := 0; d < 7; d++ {
+		fmt.Printf("%7s", sheet.Day(d).Format("Mon 2"))
+	}
+	fmt.Printf("%8s\n", "Total")
+
+	cell := func(d time.Duration) string {
+		if d == 0 {
+			return "-"
+		}
+		return jira.FormatHours(d)
+	}
+	for _, r := range sheet.Rows {
+		label := r.IssueKey + "  " + r.Summary
+		// By display width, so non-ASCII summaries keep the columns aligned
+		fmt.Print(runewidth.FillRight(runewidth.Truncate(label, 38, "..."), 40))
+		for d := 0; d < 7; d++ {
+			fmt.Printf("%7s", cell(r.Days[d]))
+		}
+		fmt.Printf("%8s\n", cell(r.Total()))
+	}
+
+	fmt.Println(strings.Repeat("-", 40+7*7+8))
+	fmt.Printf("%-40s", "Total")
+	var short []string
+	target := time.Duration(cfg.DailyTargetHours * float64(time.Hour))
+	for d := 0; d < 7; d++ {
+		total := sheet.DayTotal(d)
+		mark := ""
+		if target > 0 && d < 5 && sheet.Day(d).Before(time.Now()) && total < target {
+			mark = "!"
+			short = append(short, sheet.Day(d).Format("Mon"))
+		}
+		fmt.Printf("%7s", cell(total)+mark)
+	}
+	fmt.Printf("%8s\n", cell(sheet.Total()))
+
+	if len(short) > 0 {
+		fmt.Printf("\nBelow the %s daily target: %s\n", jira.FormatHours(target), strings.Join(short, ", "))
+	}
+	return nil
+}
+
+// parseWeek resolves the --week flag to the Monday starting that week.
+func parseWeek(week string, now time.Time) (time.Time, error) {
+	if week == "" {
+		return jira.WeekStart(now), nil
+	}
+	if offset, err := strconv.Atoi(week); err == nil {
+		return jira.WeekStart(now).AddDate(0, 0, 7*offset), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", week, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --week %q: use YYYY-MM-DD or an offset like -1", week)
+	}
+	return jira.WeekStart(day), nil
+}
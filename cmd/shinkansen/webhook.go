@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/daemon"
+	"github.com/temujinlabs/shinkansen/internal/webhook"
+)
+
+const webhookUsage = "usage: shinkansen webhook serve [-addr HOST:PORT] [-path PATH]"
+
+// runWebhook implements `shinkansen webhook serve`. Changes are announced
+// through the running daemon, or through one started in this process.
+func runWebhook(args []string) error {
+	if len(args) == 0 || args[0] != "serve" {
+		return fmt.Errorf(webhookUsage)
+	}
+	fs := flag.NewFlagSet("webhook serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:8085", "Address to listen on")
+	path := fs.String("path", "/webhook", "URL path Jira posts to")
+	fs.Parse(args[1:])
+
+	cfg, client, store, err := openSession()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if cfg.WebhookSecret == "" {
+		return fmt.Errorf("set webhook_secret in config.json, and the same secret on the Jira webhook")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	h := webhook.NewHandler(client, store, cfg.Scopes(), cfg.WebhookSecret)
+	if d, err := daemon.Dial(); err == nil {
+		defer d.Close()
+		h.OnChange = func(keys []string, events []cache.Event) {
+			if err := d.Changed(keys, events); err != nil {
+				log.Printf("daemon: %v", err)
+			}
+		}
+		log.Printf("Announcing changes through the running daemon")
+	} else {
+		srv := daemon.NewServer(cfg, client, store)
+		h.OnChange = srv.Changed
+		go func() {
+			if err := srv.Run(ctx); err != nil {
+				log.Printf("daemon: %v", err)
+			}
+		}()
+		log.Printf("Started a sync daemon in this process")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(*path, h)
+	server := &http.Server{Addr: *addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Printf("Listening for Jira webhooks on http://%s%s", *addr, *path)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// createMetaTTL is how long cached create screens are used without asking
+// Jira again. They change rarely, and refetching on every form open is slow.
+const createMetaTTL = 24 * time.Hour
+
+// cachedCreateMeta loads a createmeta entry into v; an empty issueTypeID
+// holds the project's issue type list. It returns when it was fetched.
+func (s *Store) cachedCreateMeta(projectKey, issueTypeID string, v interface{}) (time.Time, error) {
+	var data, fetched string
+	err := s.db.QueryRow(
+		"SELECT data, fetched_at FROM create_meta WHERE project_key = ? AND issue_type_id = ?",
+		projectKey, issueTypeID,
+	).Scan(&data, &fetched)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return time.Time{}, err
+	}
+	t, _ := time.Parse(time.RFC3339, fetched)
+	return t, nil
+}
+
+func (s *Store) saveCreateMeta(projectKey, issueTypeID string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		"INSERT OR REPLACE INTO create_meta (project_key, issue_type_id, data, fetched_at) VALUES (?, ?, ?, ?)",
+		projectKey, issueTypeID, string(data), time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// createMeta serves v from the cache while fresh, otherwise calls fetch and
+// caches the result. A stale entry is still used when fetch fails.
+func createMeta(store *Store, projectKey, issueTypeID string, v interface{}, fetch func() (interface{}, error)) error {
+	fetched, cacheErr := store.cachedCreateMeta(projectKey, issueTypeID, v)
+	if cacheErr == nil && time.Since(fetched) < createMetaTTL {
+		return nil
+	}
+	fresh, err := fetch()
+	if err != nil {
+		if cacheErr == nil {
+			return nil
+		}
+		return err
+	}
+	store.saveCreateMeta(projectKey, issueTypeID, fresh)
+	data, _ := json.Marshal(fresh)
+	return json.Unmarshal(data, v)
+}
+
+// CreateIssueTypes returns the issue types that can be created in a project.
+func CreateIssueTypes(client *jira.Client, store *Store, projectKey string) ([]jira.IssueType, error) {
+	var types []jira.IssueType
+	err := createMeta(store, projectKey, "", &types, func() (interface{}, error) {
+		return client.GetCreateIssueTypes(projectKey)
+	})
+	return types, err
+}
+
+// CreateFields returns the create screen fields of an issue type in a project.
+func CreateFields(client *jira.Client, store *Store, projectKey, issueTypeID string) ([]jira.CreateField, error) {
+	var fields []jira.CreateField
+	err := createMeta(store, projectKey, issueTypeID, &fields, func() (interface{}, error) {
+		return client.GetCreateFields(projectKey, issueTypeID)
+	})
+	return fields, err
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// defaultSyncOverlap is how far before its cursor a delta sync starts, so
+// updates committed while the previous sync ran aren't missed. Issues seen
+// twice are skipped when unchanged.
+const defaultSyncOverlap = 2 * time.Minute
+
+// timezoneTTL is how long the user's Jira timezone is cached.
+const timezoneTTL = 24 * time.Hour
+
+// jqlTimeLayout is the JQL date format; JQL has minute precision.
+const jqlTimeLayout = "2006/01/02 15:04"
+
+// SetSyncOverlap sets how far back each delta sync reaches past the newest
+// update it saw last time; 0 means the default.
+func (s *Store) SetSyncOverlap(d time.Duration) {
+	s.syncOverlap = d
+}
+
+func (s *Store) overlap() time.Duration {
+	if s.syncOverlap <= 0 {
+		return defaultSyncOverlap
+	}
+	return s.syncOverlap
+}
+
+// cursor returns the newest `updated` time synced for a scope, or zero.
+func (s *Store) cursor(scope string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s.State("cursor:"+scope))
+	return t
+}
+
+func (s *Store) setCursor(scope string, t time.Time) error {
+	return s.SetState("cursor:"+scope, t.UTC().Format(time.RFC3339Nano))
+}
+
+// unchanged reports whether an issue is cached with the same `updated`
+// time, as happens in the overlap window.
+func (s *Store) unchanged(issue *jira.Issue) bool {
+	var updated string
+	err := s.db.QueryRow("SELECT updated_at FROM issues WHERE key = ?", issue.Key).Scan(&updated)
+	return err == nil && updated != "" && updated == issue.Fields.Updated
+}
+
+// jiraLocation returns the timezone of the user's Jira profile, which is
+// what JQL dates are read in. It is cached for a day; when /myself can't be
+// reached the last known zone is used, then UTC.
+func jiraLocation(client *jira.Client, store *Store) *time.Location {
+	name := store.State("timezone")
+	fetched, _ := time.Parse(time.RFC3339, store.State("timezone_at"))
+	if name == "" || time.Since(fetched) > timezoneTTL {
+		if me, err := client.GetMyself(); err == nil && me.TimeZone != "" {
+			name = me.TimeZone
+			store.SetState("timezone", name)
+			store.SetState("timezone_at", time.Now().UTC().Format(time.RFC3339))
+		}
+	}
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// deltaJQL narrows a scope to issues updated since the cursor, less the
+// overlap, written in the user's Jira timezone.
+func deltaJQL(base string, cursor time.Time, overlap time.Duration, loc *time.Location) string {
+	since := cursor.Add(-overlap).In(loc).Format(jqlTimeLayout)
+	return fmt.Sprintf("%s AND updated >= \"%s\" ORDER BY updated DESC", base, since)
+}
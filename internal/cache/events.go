@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// Kinds of notification events recorded by Sync.
+const (
+	EventStatus   = "status"
+	EventComment  = "comment"
+	EventAssigned = "assigned"
+	EventMention  = "mention"
+	EventPriority = "priority"
+)
+
+// Event is a change to one of the user's issues, noticed while syncing.
+type Event struct {
+	ID       int64
+	IssueKey string
+	Kind     string
+	Actor    string // display name of who made the change, when known
+	Text     string
+	Created  time.Time
+	Read     bool
+}
+
+// AddEvents records new events, unread.
+func (s *Store) AddEvents(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range events {
+		if _, err := tx.Exec(
+			"INSERT INTO events (issue_key, kind, actor, text, created_at, read) VALUES (?, ?, ?, ?, ?, 0)",
+			e.IssueKey, e.Kind, e.Actor, e.Text, e.Created.UTC().Format(time.RFC3339),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Events returns the latest events, newest first; with unreadOnly only
+// those not yet marked read.
+func (s *Store) Events(unreadOnly bool, limit int) ([]Event, error) {
+	query := "SELECT id, issue_key, kind, actor, text, created_at, read FROM events"
+	if unreadOnly {
+		query += " WHERE read = 0"
+	}
+	rows, err := s.db.Query(query+" ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var created string
+		var read int
+		if err := rows.Scan(&e.ID, &e.IssueKey, &e.Kind, &e.Actor, &e.Text, &created, &read); err != nil {
+			continue
+		}
+		e.Created, _ = time.Parse(time.RFC3339, created)
+		e.Read = read != 0
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// UnreadEventCount returns the number of unread events.
+func (s *Store) UnreadEventCount() (int, error) {
+	var n int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM events WHERE read = 0").Scan(&n)
+	return n, err
+}
+
+// MarkEventRead marks one event read or unread.
+func (s *Store) MarkEventRead(id int64, read bool) error {
+	_, err := s.db.Exec("UPDATE events SET read = ? WHERE id = ?", read, id)
+	return err
+}
+
+// MarkIssueEventsRead marks all events of an issue read, after it was opened.
+func (s *Store) MarkIssueEventsRead(issueKey string) error {
+	_, err := s.db.Exec("UPDATE events SET read = 1 WHERE issue_key = ?", issueKey)
+	return err
+}
+
+// MarkAllEventsRead marks every event read.
+func (s *Store) MarkAllEventsRead() error {
+	_, err := s.db.Exec("UPDATE events SET read = 1")
+	return err
+}
+
+// fieldsChanged reports whether issueEvents may record an assignment,
+// status or priority change, which needs the issue's changelog to leave
+// out the user's own changes.
+func fieldsChanged(old, issue *jira.Issue, me string) bool {
+	if old == nil {
+		return issue.Fields.Assignee != nil && issue.Fields.Assignee.AccountID == me
+	}
+	return accountID(old.Fields.Assignee) != accountID(issue.Fields.Assignee) ||
+		old.Fields.Status.Name != issue.Fields.Status.Name ||
+		old.Fields.Priority.Name != issue.Fields.Priority.Name
+}
+
+func accountID(u *jira.User) string {
+	if u == nil {
+		return ""
+	}
+	return u.AccountID
+}
+
+// changedBy returns who last changed a field according to the issue's
+// changelog, or nil when it wasn't fetched or doesn't go back that far.
+func changedBy(issue *jira.Issue, field string) *jira.User {
+	if issue.Changelog == nil {
+		return nil
+	}
+	var last *jira.ChangelogHistory
+	for i := range issue.Changelog.Histories {
+		h := &issue.Changelog.Histories[i]
+		for _, item := range h.Items {
+			if item.Field == field && (last == nil || h.CreatedTime().After(last.CreatedTime())) {
+				last = h
+			}
+		}
+	}
+	if last == nil {
+		return nil
+	}
+	return &last.Author
+}
+
+// issueEvents compares an incoming issue with its cached copy (nil when it
+// wasn't cached) and returns the changes worth telling the user about.
+// Status, comment and priority changes count on issues the user is assigned
+// to, reported or watches; assignments and mentions count anywhere. Changes
+// the changelog shows the user made themselves are left out.
+func issueEvents(old, issue *jira.Issue, me string) []Event {
+	now := time.Now()
+	event := func(kind, actor, text string) Event {
+		return Event{IssueKey: issue.Key, Kind: kind, Actor: actor, Text: text, Created: now}
+	}
+	// by returns who changed a field, or "" when unknown; ok is false when
+	// it was the user
+	by := func(field string) (actor string, ok bool) {
+		u := changedBy(issue, field)
+		if u == nil {
+			return "", true
+		}
+		return u.DisplayName, u.AccountID != me
+	}
+	var events []Event
+
+	f := &issue.Fields
+	oldAssignee, newAssignee := "", accountID(f.Assignee)
+	if old != nil {
+		oldAssignee = accountID(old.Fields.Assignee)
+	}
+	if actor, ok := by("assignee"); ok {
+		switch {
+		case newAssignee == me && oldAssignee != me:
+			events = append(events, event(EventAssigned, actor, "Assigned to you"))
+		case oldAssignee == me && newAssignee != me && old != nil:
+			to := "nobody"
+			if f.Assignee != nil {
+				to = f.Assignee.DisplayName
+			}
+			events = append(events, event(EventAssigned, actor, "Reassigned from you to "+to))
+		}
+	}
+	if old == nil {
+		return events
+	}
+
+	mine := newAssignee == me || oldAssignee == me ||
+		(f.Reporter != nil && f.Reporter.AccountID == me) ||
+		(f.Watches != nil && f.Watches.IsWatching)
+
+	if mine && old.Fields.Status.Name != f.Status.Name {
+		if actor, ok := by("status"); ok {
+			events = append(events, event(EventStatus, actor, fmt.Sprintf("%s → %s", old.Fields.Status.Name, f.Status.Name)))
+		}
+	}
+	if mine && priorityRank(f.Priority.Name) > priorityRank(old.Fields.Priority.Name) && priorityRank(old.Fields.Priority.Name) > 0 {
+		if actor, ok := by("priority"); ok {
+			events = append(events, event(EventPriority, actor, fmt.Sprintf("Priority %s → %s", old.Fields.Priority.Name, f.Priority.Name)))
+		}
+	}
+
+	// Without the cached copy's description and comments (it was synced
+	// with list fields only), there is nothing to compare them with
+	if !old.HasDetails() || !issue.HasDetails() {
+		return events
+	}
+
+	if !jira.Mentions(old.Fields.Description, me) && jira.Mentions(f.Description, me) {
+		events = append(events, event(EventMention, "", "You were mentioned in the description"))
+	}
+
+	seen := map[string]bool{}
+	if old.Fields.Comment != nil {
+		for _, c := range old.Fields.Comment.Comments {
+			seen[c.ID] = true
+		}
+	}
+	if f.Comment != nil {
+		for _, c := range f.Comment.Comments {
+			if seen[c.ID] || c.Author.AccountID == me {
+				continue
+			}
+			text := firstLine(c.BodyText())
+			switch {
+			case jira.Mentions(c.Body, me):
+				events = append(events, event(EventMention, c.Author.DisplayName, c.Author.DisplayName+" mentioned you: "+text))
+			case mine:
+				events = append(events, event(EventComment, c.Author.DisplayName, c.Author.DisplayName+": "+text))
+			}
+		}
+	}
+	return events
+}
+
+// priorityRank orders Jira's default priority schemes, higher is more
+// urgent. Unknown priorities rank 0.
+func priorityRank(name string) int {
+	switch strings.ToLower(name) {
+	case "highest", "blocker":
+		return 5
+	case "high", "critical":
+		return 4
+	case "medium", "major":
+		return 3
+	case "low", "minor":
+		return 2
+	case "lowest", "trivial":
+		return 1
+	}
+	return 0
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > 100 {
+		s = string(r[:99]) + "…"
+	}
+	return s
+}
+
+// RefreshIssue fetches one issue by key or ID, records notification events
+// for what changed since it was cached, and caches it.
+func RefreshIssue(client *jira.Client, store *Store, keyOrID string) (*jira.Issue, []Event, error) {
+	issue, err := client.GetIssue(keyOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	old, _ := store.GetIssue(issue.Key)
+	if err := store.UpsertIssue(issue); err != nil {
+		return nil, nil, err
+	}
+	var events []Event
+	if me := client.AccountID(); me != "" {
+		events = issueEvents(old, issue, me)
+		if err := store.AddEvents(events); err != nil {
+			events = nil
+		}
+	}
+	return issue, events, nil
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+func TestIssueEventsLeaveOutOwnChanges(t *testing.T) {
+	me := &jira.User{AccountID: "me", DisplayName: "Me"}
+	ann := &jira.User{AccountID: "ann", DisplayName: "Ann"}
+
+	issue := func(assignee *jira.User, status, priority string, changes ...jira.ChangelogHistory) *jira.Issue {
+		i := &jira.Issue{Key: "P-1"}
+		i.Fields.Assignee = assignee
+		i.Fields.Status.Name = status
+		i.Fields.Priority.Name = priority
+		if changes != nil {
+			i.Changelog = &jira.Changelog{Histories: changes}
+		}
+		return i
+	}
+	change := func(author *jira.User, created, field string) jira.ChangelogHistory {
+		return jira.ChangelogHistory{Author: *author, Created: created, Items: []jira.ChangelogItem{{Field: field}}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new *jira.Issue
+		want     []string
+	}{
+		{
+			name: "assigned by someone else",
+			old:  issue(nil, "To Do", "Medium"),
+			new:  issue(me, "To Do", "Medium", change(ann, "2026-05-04T10:00:00.000+0000", "assignee")),
+			want: []string{"Assigned to you"},
+		},
+		{
+			name: "assigned themselves",
+			old:  issue(nil, "To Do", "Medium"),
+			new:  issue(me, "To Do", "Medium", change(me, "2026-05-04T10:00:00.000+0000", "assignee")),
+		},
+		{
+			name: "created assigned to themselves",
+			new:  issue(me, "To Do", "Medium", change(me, "2026-05-04T10:00:00.000+0000", "assignee")),
+		},
+		{
+			name: "moved their own issue",
+			old:  issue(me, "To Do", "Medium"),
+			new: issue(me, "Done", "Medium",
+				change(ann, "2026-05-04T09:00:00.000+0000", "status"),
+				change(me, "2026-05-04T10:00:00.000+0000", "status")),
+		},
+		{
+			name: "latest status change was someone else's",
+			old:  issue(me, "To Do", "Medium"),
+			new: issue(me, "Done", "Medium",
+				change(ann, "2026-05-04T11:00:00.000+0000", "status"),
+				change(me, "2026-05-04T10:00:00.000+0000", "status")),
+			want: []string{"To Do → Done"},
+		},
+		{
+			name: "raised priority themselves",
+			old:  issue(me, "To Do", "Medium"),
+			new:  issue(me, "To Do", "High", change(me, "2026-05-04T10:00:00.000+0000", "priority")),
+		},
+		{
+			name: "no changelog, so the author is unknown",
+			old:  issue(me, "To Do", "Medium"),
+			new:  issue(me, "Done", "High"),
+			want: []string{"To Do → Done", "Priority Medium → High"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := issueEvents(tt.old, tt.new, "me")
+			var got []string
+			for _, e := range events {
+				got = append(got, e.Text)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("events = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldsChanged(t *testing.T) {
+	old := &jira.Issue{Key: "P-1"}
+	old.Fields.Status.Name = "To Do"
+	same := *old
+	if fieldsChanged(old, &same, "me") {
+		t.Error("unchanged issue needs its changelog")
+	}
+	moved := *old
+	moved.Fields.Status.Name = "Done"
+	if !fieldsChanged(old, &moved, "me") {
+		t.Error("status change doesn't need the changelog")
+	}
+	mine := &jira.Issue{Key: "P-2"}
+	mine.Fields.Assignee = &jira.User{AccountID: "me"}
+	if !fieldsChanged(nil, mine, "me") {
+		t.Error("new issue assigned to the user doesn't need the changelog")
+	}
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// fieldTables maps the search prefixes for multi-value fields to the join
+// table and column holding them.
+var fieldTables = map[string][2]string{
+	"label":     {"issue_labels", "label"},
+	"component": {"issue_components", "component"},
+	"version":   {"issue_fix_versions", "version"},
+}
+
+// indexFields rewrites the label, component and fix version rows of an issue.
+func (s *Store) indexFields(issue *jira.Issue) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	values := map[string][]string{
+		"label":     issue.Fields.Labels,
+		"component": issue.Fields.ComponentNames(),
+		"version":   issue.Fields.FixVersionNames(),
+	}
+	for kind, names := range values {
+		t := fieldTables[kind]
+		if _, err := tx.Exec("DELETE FROM "+t[0]+" WHERE issue_key = ?", issue.Key); err != nil {
+			return err
+		}
+		for _, n := range names {
+			if _, err := tx.Exec("INSERT OR IGNORE INTO "+t[0]+" (issue_key, "+t[1]+") VALUES (?, ?)", issue.Key, n); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// FieldValues returns the distinct values of "label", "component" or
+// "version" seen on cached issues, sorted by name.
+func (s *Store) FieldValues(kind string) ([]string, error) {
+	t, ok := fieldTables[kind]
+	if !ok {
+		return nil, nil
+	}
+	rows, err := s.db.Query("SELECT DISTINCT " + t[1] + " FROM " + t[0] + " ORDER BY " + t[1])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if rows.Scan(&v) == nil {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// customFieldID matches Jira custom field IDs, which end up in SQL JSON paths.
+var customFieldID = regexp.MustCompile(`^customfield_[0-9]+$`)
+
+// SetCustomFields registers custom fields by friendly name so that name:x
+// words in SearchIssues filter on them. Malformed IDs are ignored.
+func (s *Store) SetCustomFields(fields map[string]string) {
+	s.customFields = make(map[string]string)
+	for name, id := range fields {
+		if customFieldID.MatchString(id) {
+			s.customFields[name] = id
+		}
+	}
+}
+
+// fieldFilters turns label:x, component:x, version:x and custom field words
+// in a query into SQL conditions on the issues table.
+func (s *Store) fieldFilters(query string) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+	for _, word := range strings.Fields(query) {
+		prefix, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			continue
+		}
+		if t, known := fieldTables[prefix]; known {
+			where = append(where, "key IN (SELECT issue_key FROM "+t[0]+" WHERE "+t[1]+" = ? COLLATE NOCASE)")
+			args = append(args, value)
+			continue
+		}
+		if id, known := s.customFields[prefix]; known {
+			cond, condArgs := customFieldFilter(id, value)
+			where = append(where, cond)
+			args = append(args, condArgs...)
+		}
+	}
+	return where, args
+}
+
+// customFieldFilter matches a custom field in raw_json: scalars by equal
+// value (numbers numerically), options, users and arrays by a contained
+// "value", "name" or "displayName" string.
+func customFieldFilter(id, value string) (string, []interface{}) {
+	path := "$.fields." + id
+	cond := "(json_extract(raw_json, '" + path + "') = ? COLLATE NOCASE" +
+		" OR (json_type(raw_json, '" + path + "') IN ('object', 'array') AND json_extract(raw_json, '" + path + "') LIKE ?)"
+	args := []interface{}{value, `%:"` + value + `"%`}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		cond += " OR json_extract(raw_json, '" + path + "') = ?"
+		args = append(args, n)
+	}
+	return cond + ")", args
+}
+
+// freeText returns the words of a query that aren't field filters.
+func (s *Store) freeText(query string) []string {
+	var words []string
+	for _, word := range strings.Fields(query) {
+		if prefix, value, ok := strings.Cut(word, ":"); ok && value != "" {
+			if _, known := fieldTables[prefix]; known {
+				continue
+			}
+			if _, known := s.customFields[prefix]; known {
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+	return words
+}
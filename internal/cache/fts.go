@@ -0,0 +1,273 @@
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// Markers around matched text in SearchHit snippets and titles.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// searchLimit caps the results of SearchIssues.
+const searchLimit = 100
+
+// fuzzyBelow is how few full-text hits make SearchIssues add fuzzy matches
+// on keys and titles.
+const fuzzyBelow = 10
+
+// SearchHit is an issue found by SearchIssues.
+type SearchHit struct {
+	Issue   jira.Issue
+	Title   string // summary with the matches marked
+	Snippet string // matching description or comment text, marked; "" when the summary matched
+	Fuzzy   bool   // found by the fuzzy fallback rather than the index
+}
+
+// migrateFullText adds the full-text index over summaries, descriptions and
+// comment bodies, and fills it from the cached issues.
+func migrateFullText(tx *sql.Tx) error {
+	if _, err := tx.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS issue_fts USING fts5(
+		issue_key UNINDEXED, summary, description, comments,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`); err != nil {
+		return err
+	}
+	rows, err := tx.Query("SELECT raw_json FROM issues")
+	if err != nil {
+		return err
+	}
+	var issues []jira.Issue
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			continue
+		}
+		var issue jira.Issue
+		if err := json.Unmarshal([]byte(raw), &issue); err == nil {
+			issues = append(issues, issue)
+		}
+	}
+	rows.Close()
+	for i := range issues {
+		if err := indexText(tx, &issues[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execer is a *sql.DB or *sql.Tx.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// indexText replaces an issue's row in the full-text index.
+func indexText(db execer, issue *jira.Issue) error {
+	if _, err := db.Exec("DELETE FROM issue_fts WHERE issue_key = ?", issue.Key); err != nil {
+		return err
+	}
+	var comments []string
+	if issue.Fields.Comment != nil {
+		for _, c := range issue.Fields.Comment.Comments {
+			comments = append(comments, c.BodyText())
+		}
+	}
+	_, err := db.Exec(
+		"INSERT INTO issue_fts (issue_key, summary, description, comments) VALUES (?, ?, ?, ?)",
+		issue.Key, issue.Fields.Summary, issue.DescriptionText(), strings.Join(comments, "\n"),
+	)
+	return err
+}
+
+// ftsQuery turns free-text words into an FTS5 query matching all of them,
+// each as a prefix. Words without letters or digits are dropped.
+func ftsQuery(words []string) string {
+	var terms []string
+	for _, w := range words {
+		if !strings.ContainsFunc(w, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// SearchIssues finds cached issues for a query. Free text is matched
+// against summaries, descriptions and comments through the full-text index,
+// every word as a prefix, best matches first (summary hits weigh most).
+// When that finds little, issue keys and titles are also matched fuzzily,
+// the query's letters in order with gaps allowed. Words of the form label:x,
+// component:x, version:x and <custom field>:x filter on those fields.
+func (s *Store) SearchIssues(query string) ([]SearchHit, error) {
+	where, args := s.fieldFilters(query)
+	words := s.freeText(query)
+	if len(words) == 0 {
+		if len(where) == 0 {
+			return nil, nil
+		}
+		return s.filterIssues(where, args)
+	}
+
+	var hits []SearchHit
+	if match := ftsQuery(words); match != "" {
+		var err error
+		if hits, err = s.textSearch(match, where, args); err != nil {
+			return nil, err
+		}
+	}
+	if len(hits) < fuzzyBelow {
+		fuzzy, err := s.fuzzySearch(strings.Join(words, " "), where, args, hits)
+		if err != nil {
+			return hits, err
+		}
+		hits = append(hits, fuzzy...)
+	}
+	return hits, nil
+}
+
+// textSearch runs a full-text query, with field filters on the issues.
+func (s *Store) textSearch(match string, where []string, args []interface{}) ([]SearchHit, error) {
+	mark := fmt.Sprintf("'%s', '%s'", MatchStart, MatchEnd)
+	q := `SELECT i.raw_json, highlight(issue_fts, 1, ` + mark + `),
+			snippet(issue_fts, 2, ` + mark + `, '…', 12), snippet(issue_fts, 3, ` + mark + `, '…', 12)
+		FROM issue_fts JOIN issues i ON i.key = issue_fts.issue_key
+		WHERE issue_fts MATCH ?`
+	for _, w := range where {
+		q += " AND " + w
+	}
+	q += fmt.Sprintf(" ORDER BY bm25(issue_fts, 0, 10.0, 3.0, 1.0) LIMIT %d", searchLimit)
+
+	rows, err := s.db.Query(q, append([]interface{}{match}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var raw, title, desc, comments string
+		if err := rows.Scan(&raw, &title, &desc, &comments); err != nil {
+			continue
+		}
+		var hit SearchHit
+		if err := json.Unmarshal([]byte(raw), &hit.Issue); err != nil {
+			continue
+		}
+		hit.Title = title
+		switch {
+		case strings.Contains(desc, MatchStart):
+			hit.Snippet = desc
+		case strings.Contains(comments, MatchStart):
+			hit.Snippet = comments
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// filterIssues returns the issues matching field filters alone.
+func (s *Store) filterIssues(where []string, args []interface{}) ([]SearchHit, error) {
+	rows, err := s.db.Query(
+		fmt.Sprintf("SELECT raw_json FROM issues WHERE %s ORDER BY updated_at DESC LIMIT %d", strings.Join(where, " AND "), searchLimit),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			continue
+		}
+		var hit SearchHit
+		if err := json.Unmarshal([]byte(raw), &hit.Issue); err != nil {
+			continue
+		}
+		hit.Title = hit.Issue.Fields.Summary
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// backfillLimit caps how many issues BackfillDetails fetches per run, one
+// search page.
+const backfillLimit = 100
+
+// BackfillDetails fetches the description and comments of cached issues
+// synced with list fields only, so full-text search covers them before
+// they are opened, and returns how many it filled in. Each run takes the
+// most recently updated ones.
+func BackfillDetails(client *jira.Client, store *Store) (int, error) {
+	keys, err := store.keysWithoutDetails(backfillLimit)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+
+	filled := 0
+	err = client.SearchEach("key in ("+strings.Join(keys, ", ")+")", jira.FieldsFull, func(issues []jira.Issue) error {
+		for i := range issues {
+			if err := store.UpsertIssue(&issues[i]); err != nil {
+				return err
+			}
+			filled++
+		}
+		return nil
+	})
+	var apiErr *jira.APIError
+	if !errors.As(err, &apiErr) {
+		return filled, err
+	}
+
+	// Jira rejects the whole query when one key no longer exists; fetch the
+	// issues one by one and leave the missing ones to reconciling
+	filled = 0
+	for _, key := range keys {
+		issue, err := client.GetIssue(key)
+		if err != nil && !errors.As(err, &apiErr) {
+			return filled, err
+		}
+		if err != nil {
+			continue
+		}
+		if err := store.UpsertIssue(issue); err != nil {
+			return filled, err
+		}
+		filled++
+	}
+	return filled, nil
+}
+
+// keysWithoutDetails returns up to limit issues cached without their
+// description and comments, most recently updated first.
+func (s *Store) keysWithoutDetails(limit int) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT key FROM issues WHERE json_extract(raw_json, '$.fields.comment') IS NULL ORDER BY updated_at DESC LIMIT ?",
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Fuzzy scoring, after fzf: every matched character scores, more at the
+// start of a word and in runs of consecutive matches; gaps cost.
+const (
+	scoreMatch       = 16
+	bonusBoundary    = 8
+	bonusConsecutive = 4
+	penaltyGapStart  = 3
+	penaltyGapExtend = 1
+)
+
+// fuzzyMatch reports whether pattern's characters appear in text in order,
+// ignoring case and spaces in the pattern, and scores the match. The match
+// is narrowed to the shortest window ending where the greedy scan ended, as
+// fzf does. positions are the matched rune indexes in text.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	pat := []rune(strings.Join(strings.Fields(pattern), ""))
+	for i, r := range pat {
+		pat[i] = unicode.ToLower(r)
+	}
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	if len(pat) == 0 {
+		return 0, nil, false
+	}
+
+	// Forward: the earliest position each pattern rune can end at
+	pi, end := 0, -1
+	for i, r := range lower {
+		if r == pat[pi] {
+			if pi++; pi == len(pat) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	// Backward from there: the latest start, for the tightest window
+	pi = len(pat) - 1
+	positions = make([]int, len(pat))
+	for i := end; i >= 0 && pi >= 0; i-- {
+		if lower[i] == pat[pi] {
+			positions[pi] = i
+			pi--
+		}
+	}
+
+	run := 0
+	for n, i := range positions {
+		score += scoreMatch
+		if i == 0 || isBoundary(runes[i-1], runes[i]) {
+			score += bonusBoundary
+		}
+		if n > 0 && positions[n-1] == i-1 {
+			run++
+			score += bonusConsecutive * run
+		} else {
+			run = 0
+			if n > 0 {
+				score -= penaltyGapStart + penaltyGapExtend*(i-positions[n-1]-2)
+			}
+		}
+	}
+	return score, positions, true
+}
+
+// isBoundary reports whether cur starts a word: after a separator, or an
+// upper-case letter after a lower-case one.
+func isBoundary(prev, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// markPositions wraps the runes at positions in match markers.
+func markPositions(text string, positions []int) string {
+	at := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		at[p] = true
+	}
+	var b strings.Builder
+	open := false
+	for i, r := range []rune(text) {
+		if at[i] != open {
+			if open = at[i]; open {
+				b.WriteString(MatchStart)
+			} else {
+				b.WriteString(MatchEnd)
+			}
+		}
+		b.WriteRune(r)
+	}
+	if open {
+		b.WriteString(MatchEnd)
+	}
+	return b.String()
+}
+
+// fuzzySearch scores every cached issue's key and summary against the
+// query, with field filters applied, skipping issues already in seen. The
+// better of the key and summary scores counts.
+func (s *Store) fuzzySearch(query string, where []string, args []interface{}, seen []SearchHit) ([]SearchHit, error) {
+	q := "SELECT key, summary FROM issues"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skip := make(map[string]bool, len(seen))
+	for _, h := range seen {
+		skip[h.Issue.Key] = true
+	}
+
+	type scored struct {
+		key, title string
+		score      int
+	}
+	var matches []scored
+	for rows.Next() {
+		var key, summary string
+		if err := rows.Scan(&key, &summary); err != nil || skip[key] {
+			continue
+		}
+		keyScore, _, keyOK := fuzzyMatch(query, key)
+		sumScore, positions, sumOK := fuzzyMatch(query, summary)
+		switch {
+		case sumOK && (!keyOK || sumScore > keyScore):
+			matches = append(matches, scored{key, markPositions(summary, positions), sumScore})
+		case keyOK:
+			matches = append(matches, scored{key, summary, keyScore})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	matches = matches[:min(max(0, searchLimit-len(seen)), len(matches))]
+	hits := make([]SearchHit, 0, len(matches))
+	for _, m := range matches {
+		issue, err := s.GetIssue(m.key)
+		if err != nil {
+			continue
+		}
+		hits = append(hits, SearchHit{Issue: *issue, Title: m.title, Fuzzy: true})
+	}
+	return hits, nil
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// UpsertHistory stores changelog entries for an issue. Entries are keyed by
+// their Jira history ID, so re-fetching a changelog never duplicates rows.
+func (s *Store) UpsertHistory(issueKey string, histories []jira.ChangelogHistory) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, h := range histories {
+		items, _ := json.Marshal(h.Items)
+		author, _ := json.Marshal(h.Author)
+		if _, err := tx.Exec(
+			"INSERT OR REPLACE INTO issue_history (issue_key, history_id, author, created, items_json) VALUES (?, ?, ?, ?, ?)",
+			issueKey, h.ID, string(author), h.Created, string(items),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetHistory returns cached changelog entries for an issue, newest first.
+func (s *Store) GetHistory(issueKey string) ([]jira.ChangelogHistory, error) {
+	rows, err := s.db.Query(
+		"SELECT history_id, author, created, items_json FROM issue_history WHERE issue_key = ?",
+		issueKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []jira.ChangelogHistory
+	for rows.Next() {
+		var h jira.ChangelogHistory
+		var author, items string
+		if err := rows.Scan(&h.ID, &author, &h.Created, &items); err != nil {
+			continue
+		}
+		json.Unmarshal([]byte(author), &h.Author)
+		json.Unmarshal([]byte(items), &h.Items)
+		histories = append(histories, h)
+	}
+	// Timestamps carry the author's UTC offset, so sort on parsed time.
+	sort.SliceStable(histories, func(i, j int) bool {
+		return histories[i].CreatedTime().After(histories[j].CreatedTime())
+	})
+	return histories, nil
+}
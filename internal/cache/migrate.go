@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// migration moves the cache schema up one version. Each runs in its own
+// transaction together with the version bump.
+type migration struct {
+	name string
+	up   func(tx *sql.Tx) error
+}
+
+// migrations are applied in order; the schema version is how many have
+// been. Released migrations must never change: add a new one to alter a
+// table (ALTER TABLE ... ADD COLUMN, or copy into a new table), and backfill
+// from raw_json where it can be.
+var migrations = []migration{
+	{"initial schema", migrateInitial},
+	{"full-text index", migrateFullText},
+	{"queued worklog estimates", migrateWorklogEstimates},
+}
+
+// SchemaVersion is the cache schema this build writes.
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// NewerSchemaError is returned when the cache was written by a newer
+// shinkansen.
+type NewerSchemaError struct {
+	Version, Supported int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("the cache has schema version %d, but this shinkansen only knows up to %d; upgrade shinkansen, or run 'shinkansen cache reset' to rebuild the cache from Jira",
+		e.Version, e.Supported)
+}
+
+// Version returns the cache's schema version, 0 for a new cache.
+func (s *Store) Version() (int, error) {
+	if _, err := s.db.Exec("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)"); err != nil {
+		return 0, err
+	}
+	var v int
+	err := s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&v)
+	return v, err
+}
+
+// Migrate applies the migrations the cache hasn't had yet and returns the
+// versions before and after. A cache from a newer build is refused rather
+// than touched.
+func (s *Store) Migrate() (from, to int, err error) {
+	from, err = s.Version()
+	if err != nil {
+		return 0, 0, err
+	}
+	if from > len(migrations) {
+		return from, from, &NewerSchemaError{Version: from, Supported: len(migrations)}
+	}
+	for v := from; v < len(migrations); v++ {
+		if err := s.apply(v+1, migrations[v]); err != nil {
+			return from, v, fmt.Errorf("migration %d (%s): %w", v+1, migrations[v].name, err)
+		}
+	}
+	return from, len(migrations), nil
+}
+
+func (s *Store) apply(version int, m migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM schema_version"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES (?)", version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Path returns the cache database file.
+func Path() (string, error) {
+	return dbPath()
+}
+
+// Reset deletes the cache database; the next start rebuilds it from Jira.
+// Queued worklogs and timers are lost with it.
+func Reset() error {
+	path, err := dbPath()
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{path, path + "-wal", path + "-shm"} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Info summarizes the cache for `shinkansen cache info`.
+type Info struct {
+	Version         int
+	Issues          int
+	ByScope         map[string]int
+	PendingWorklogs int
+	UnreadEvents    int
+	LastSync        time.Time
+}
+
+// Info counts what the cache holds. Only the version is filled in for a
+// cache from a newer build; counts of tables an older cache lacks are 0.
+func (s *Store) Info() (*Info, error) {
+	v, err := s.Version()
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{Version: v, ByScope: map[string]int{}}
+	if v > len(migrations) {
+		return info, nil
+	}
+	tables, err := s.tables()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []struct {
+		table, query string
+		n            *int
+	}{
+		{"issues", "SELECT COUNT(*) FROM issues", &info.Issues},
+		{"pending_worklogs", "SELECT COUNT(*) FROM pending_worklogs", &info.PendingWorklogs},
+		{"events", "SELECT COUNT(*) FROM events WHERE read = 0", &info.UnreadEvents},
+	} {
+		if !tables[c.table] {
+			continue
+		}
+		if err := s.db.QueryRow(c.query).Scan(c.n); err != nil {
+			return nil, fmt.Errorf("count %s: %w", c.table, err)
+		}
+	}
+	if tables["sync_log"] {
+		if info.LastSync, err = s.LastSync(); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("last sync: %w", err)
+		}
+	}
+	if !tables["issue_scopes"] {
+		return info, nil
+	}
+
+	rows, err := s.db.Query("SELECT scope, COUNT(*) FROM issue_scopes GROUP BY scope")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var scope string
+		var n int
+		if err := rows.Scan(&scope, &n); err != nil {
+			return nil, err
+		}
+		info.ByScope[scope] = n
+	}
+	return info, rows.Err()
+}
+
+// tables returns the names of the cache's tables.
+func (s *Store) tables() (map[string]bool, error) {
+	rows, err := s.db.Query("SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables[name] = true
+	}
+	return tables, rows.Err()
+}
+
+// migrateInitial creates the schema as it was before versioning. Its
+// statements are idempotent so caches from then are adopted as version 1.
+func migrateInitial(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS issues (
+		key TEXT PRIMARY KEY,
+		summary TEXT,
+		status TEXT,
+		assignee TEXT,
+		priority TEXT,
+		issue_type TEXT,
+		project_key TEXT,
+		sprint_id INTEGER,
+		updated_at TEXT,
+		raw_json TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS projects (
+		id TEXT PRIMARY KEY,
+		key TEXT,
+		name TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS sprints (
+		id INTEGER PRIMARY KEY,
+		name TEXT,
+		state TEXT,
+		board_id INTEGER,
+		start_date TEXT,
+		end_date TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS transitions (
+		issue_key TEXT,
+		transition_id TEXT,
+		name TEXT,
+		PRIMARY KEY (issue_key, transition_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS transition_state (
+		issue_key TEXT PRIMARY KEY,
+		status TEXT,
+		fetched_at TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS sync_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		last_sync TEXT,
+		items_synced INTEGER,
+		duration_ms INTEGER
+	);
+
+	CREATE TABLE IF NOT EXISTS jql_filters (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		jql TEXT NOT NULL,
+		used_at TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS issue_history (
+		issue_key TEXT,
+		history_id TEXT,
+		author TEXT,
+		created TEXT,
+		items_json TEXT,
+		PRIMARY KEY (issue_key, history_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS timers (
+		issue_key TEXT PRIMARY KEY,
+		started_at TEXT,
+		running_since TEXT,
+		accumulated_ms INTEGER,
+		last_seen TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS pending_worklogs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		issue_key TEXT NOT NULL,
+		time_spent TEXT NOT NULL,
+		started TEXT,
+		comment TEXT,
+		adjust_estimate TEXT,
+		created_at TEXT,
+		last_error TEXT DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS issue_labels (
+		issue_key TEXT,
+		label TEXT,
+		PRIMARY KEY (issue_key, label)
+	);
+
+	CREATE TABLE IF NOT EXISTS issue_components (
+		issue_key TEXT,
+		component TEXT,
+		PRIMARY KEY (issue_key, component)
+	);
+
+	CREATE TABLE IF NOT EXISTS issue_fix_versions (
+		issue_key TEXT,
+		version TEXT,
+		PRIMARY KEY (issue_key, version)
+	);
+
+	CREATE TABLE IF NOT EXISTS create_meta (
+		project_key TEXT,
+		issue_type_id TEXT,
+		data TEXT,
+		fetched_at TEXT,
+		PRIMARY KEY (project_key, issue_type_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS permissions (
+		project_key TEXT,
+		permission TEXT,
+		granted INTEGER,
+		fetched_at TEXT,
+		PRIMARY KEY (project_key, permission)
+	);
+
+	CREATE TABLE IF NOT EXISTS events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		issue_key TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		actor TEXT DEFAULT '',
+		text TEXT,
+		created_at TEXT,
+		read INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_events_read ON events(read);
+
+	CREATE TABLE IF NOT EXISTS sync_state (
+		key TEXT PRIMARY KEY,
+		value TEXT
+	);
+
+	CREATE TABLE IF NOT EXISTS issue_scopes (
+		issue_key TEXT NOT NULL,
+		scope TEXT NOT NULL,
+		PRIMARY KEY (issue_key, scope)
+	);
+	CREATE INDEX IF NOT EXISTS idx_issue_scopes_scope ON issue_scopes(scope);
+	CREATE INDEX IF NOT EXISTS idx_issues_status ON issues(status);
+	CREATE INDEX IF NOT EXISTS idx_issue_labels_label ON issue_labels(label);
+	CREATE INDEX IF NOT EXISTS idx_issue_components_component ON issue_components(component);
+	CREATE INDEX IF NOT EXISTS idx_issue_fix_versions_version ON issue_fix_versions(version);
+	CREATE INDEX IF NOT EXISTS idx_issues_project ON issues(project_key);
+	CREATE INDEX IF NOT EXISTS idx_issues_assignee ON issues(assignee);
+	`)
+	return err
+}
+
+// migrateWorklogEstimates keeps the new or reduce-by estimate of queued
+// worklogs, which the "new" and "manual" adjust modes need.
+func migrateWorklogEstimates(tx *sql.Tx) error {
+	for _, col := range []string{"new_estimate", "reduce_by"} {
+		if _, err := tx.Exec("ALTER TABLE pending_worklogs ADD COLUMN " + col + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
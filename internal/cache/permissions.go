@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// permissionsTTL is how long cached permissions are trusted. Permission
+// schemes change rarely; an hour keeps revoked actions from lingering long.
+const permissionsTTL = time.Hour
+
+// cachedPermissions returns the stored permissions of a project and when
+// they were fetched.
+func (s *Store) cachedPermissions(projectKey string) (map[string]bool, time.Time, error) {
+	rows, err := s.db.Query("SELECT permission, granted, fetched_at FROM permissions WHERE project_key = ?", projectKey)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	perms := make(map[string]bool)
+	var fetched time.Time
+	for rows.Next() {
+		var perm, at string
+		var granted bool
+		if err := rows.Scan(&perm, &granted, &at); err != nil {
+			return nil, time.Time{}, err
+		}
+		perms[perm] = granted
+		if t, err := time.Parse(time.RFC3339, at); err == nil && (fetched.IsZero() || t.Before(fetched)) {
+			fetched = t
+		}
+	}
+	return perms, fetched, rows.Err()
+}
+
+func (s *Store) savePermissions(projectKey string, perms map[string]bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM permissions WHERE project_key = ?", projectKey); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for perm, granted := range perms {
+		if _, err := tx.Exec(
+			"INSERT INTO permissions (project_key, permission, granted, fetched_at) VALUES (?, ?, ?, ?)",
+			projectKey, perm, granted, now,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Permissions returns the current user's jira.ActionPermissions in a
+// project, from the cache while fresh. When Jira can't be reached, stale
+// cached permissions are used.
+func Permissions(client *jira.Client, store *Store, projectKey string) (map[string]bool, error) {
+	cached, fetched, cacheErr := store.cachedPermissions(projectKey)
+	complete := cacheErr == nil
+	for _, p := range jira.ActionPermissions {
+		if _, ok := cached[p]; !ok {
+			complete = false
+		}
+	}
+	if complete && time.Since(fetched) < permissionsTTL {
+		return cached, nil
+	}
+
+	perms, err := client.MyPermissions(projectKey, jira.ActionPermissions...)
+	if err != nil {
+		if complete {
+			return cached, nil
+		}
+		return nil, err
+	}
+	store.savePermissions(projectKey, perms)
+	return perms, nil
+}
@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// reconcileInterval is how often Sync checks the whole scope for issues
+// that were deleted, moved or fell out of it. Delta syncs can't see those.
+const reconcileInterval = time.Hour
+
+// issueKeyTables hold per-issue rows that follow an issue when its key
+// changes.
+var issueKeyTables = []string{
+	"issue_history", "transitions", "issue_labels", "issue_components",
+	"issue_fix_versions", "timers", "pending_worklogs", "events",
+	"issue_scopes", "transition_state", "issue_fts",
+}
+
+// Reconcile compares the cache with the issues matching the scopes' JQL,
+// by scope name. Cached issues whose ID now has another key (moved to
+// another project) are rekeyed; those in no scope any more (deleted, moved
+// away, resolved too long ago) are pruned, and every issue is re-tagged
+// with the scopes that include it. Nothing is pruned when any search fails
+// or all come back empty, in case a search was wrongly empty.
+func Reconcile(client *jira.Client, store *Store, scopes map[string]string) (pruned, rekeyed int, err error) {
+	keyByID := make(map[string]string)
+	members := make(map[string][]string, len(scopes))
+	for name, jql := range scopes {
+		refs, err := client.SearchKeys(jql)
+		if err != nil {
+			return 0, 0, fmt.Errorf("scope %s: %w", name, err)
+		}
+		for _, r := range refs {
+			keyByID[r.ID] = r.Key
+			members[name] = append(members[name], r.Key)
+		}
+	}
+	if len(keyByID) == 0 {
+		return 0, 0, nil
+	}
+
+	cached, err := store.issueIDs()
+	if err != nil {
+		return 0, 0, err
+	}
+	for key, id := range cached {
+		newKey, ok := keyByID[id]
+		switch {
+		case !ok:
+			if err := store.DeleteIssue(key); err != nil {
+				return pruned, rekeyed, err
+			}
+			pruned++
+		case newKey != key:
+			if err := store.RekeyIssue(key, newKey); err != nil {
+				return pruned, rekeyed, err
+			}
+			rekeyed++
+		}
+	}
+	return pruned, rekeyed, store.retagScopes(members)
+}
+
+// reconcileScopes reconciles against every configured scope and records
+// when, and for which scopes, it did.
+func reconcileScopes(client *jira.Client, store *Store, all []config.SyncScope) (pruned, rekeyed int, err error) {
+	jqls := make(map[string]string, len(all))
+	for _, scope := range all {
+		if jqls[scope.Name], err = scopeJQL(client, store, scope); err != nil {
+			return 0, 0, err
+		}
+	}
+	if pruned, rekeyed, err = Reconcile(client, store, jqls); err != nil {
+		return pruned, rekeyed, err
+	}
+	if err := store.SetState("reconciled_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return pruned, rekeyed, err
+	}
+	return pruned, rekeyed, store.SetState("reconciled_scopes", scopeSet(all))
+}
+
+// reconcileDue reports whether the last reconciliation is older than
+// reconcileInterval, or was for a different set of scopes.
+func reconcileDue(store *Store, all []config.SyncScope) bool {
+	if store.State("reconciled_scopes") != scopeSet(all) {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, store.State("reconciled_at"))
+	return err != nil || time.Since(last) >= reconcileInterval
+}
+
+// scopeSet describes the configured scopes, to notice when they change.
+func scopeSet(all []config.SyncScope) string {
+	parts := make([]string, len(all))
+	for i, s := range all {
+		parts[i] = fmt.Sprintf("%s=%s/%d/%s", s.Name, s.Project, s.Board, s.JQL)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n")
+}
+
+// issueIDs returns the Jira ID of every cached issue by key.
+func (s *Store) issueIDs() (map[string]string, error) {
+	rows, err := s.db.Query("SELECT key, COALESCE(json_extract(raw_json, '$.id'), '') FROM issues")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			continue
+		}
+		if id != "" {
+			ids[key] = id
+		}
+	}
+	return ids, rows.Err()
+}
+
+// FollowMoves rekeys cached issues that were moved to another project,
+// looking up their new keys by ID, so timers, queued worklogs, events and
+// the issues' other rows follow them. keyByID maps issue IDs to the keys
+// they had; issues Jira doesn't return are dropped from the cache.
+func FollowMoves(client *jira.Client, store *Store, keyByID map[string]string) error {
+	ids := make([]string, 0, len(keyByID))
+	for id := range keyByID {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	var refs []jira.Issue
+	if len(ids) > 0 {
+		sort.Strings(ids)
+		var err error
+		if refs, err = client.SearchKeys("id in (" + strings.Join(ids, ", ") + ")"); err != nil {
+			return err
+		}
+	}
+	for _, r := range refs {
+		if oldKey, ok := keyByID[r.ID]; ok {
+			if err := store.RekeyIssue(oldKey, r.Key); err != nil {
+				return err
+			}
+			delete(keyByID, r.ID)
+		}
+	}
+	for _, oldKey := range keyByID {
+		if err := store.DeleteIssue(oldKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RekeyIssue moves a cached issue and its rows from oldKey to newKey, after
+// Jira changed its key. When newKey is already cached (a delta sync picked
+// up the moved issue), the old row is dropped and its rows are merged.
+func (s *Store) RekeyIssue(oldKey, newKey string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, t := range issueKeyTables {
+		if _, err := tx.Exec("UPDATE OR IGNORE "+t+" SET issue_key = ? WHERE issue_key = ?", newKey, oldKey); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM "+t+" WHERE issue_key = ?", oldKey); err != nil {
+			return err
+		}
+	}
+
+	var exists int
+	tx.QueryRow("SELECT COUNT(*) FROM issues WHERE key = ?", newKey).Scan(&exists)
+	if exists > 0 {
+		_, err = tx.Exec("DELETE FROM issues WHERE key = ?", oldKey)
+	} else {
+		_, err = tx.Exec("UPDATE issues SET key = ?, raw_json = json_set(raw_json, '$.key', ?) WHERE key = ?", newKey, newKey, oldKey)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// recentJQL limits every scope to what the views show: unresolved issues
+// and those resolved in the last 14 days (Done column).
+const recentJQL = "(resolution = Unresolved OR resolutiondate >= -14d)"
+
+// orderBy matches a trailing ORDER BY clause in user JQL.
+var orderBy = regexp.MustCompile(`(?is)\s+order\s+by\s+.*$`)
+
+// scopeJQL returns the JQL selecting a scope's issues. Boards are synced
+// through their saved filter.
+func scopeJQL(client *jira.Client, store *Store, scope config.SyncScope) (string, error) {
+	switch {
+	case scope.Project != "":
+		return fmt.Sprintf("project = %s AND %s", scope.Project, recentJQL), nil
+	case scope.Board > 0:
+		id, err := boardFilter(client, store, scope.Board)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("filter = %s AND %s", id, recentJQL), nil
+	case scope.JQL != "":
+		return fmt.Sprintf("(%s) AND %s", orderBy.ReplaceAllString(scope.JQL, ""), recentJQL), nil
+	}
+	return recentJQL, nil
+}
+
+// boardFilter returns a board's filter ID, looked up once and kept.
+func boardFilter(client *jira.Client, store *Store, board int) (string, error) {
+	key := "board_filter:" + strconv.Itoa(board)
+	if id := store.State(key); id != "" {
+		return id, nil
+	}
+	id, err := client.GetBoardFilterID(board)
+	if err != nil {
+		return "", fmt.Errorf("board %d: %w", board, err)
+	}
+	store.SetState(key, id)
+	return id, nil
+}
+
+// dueSlack lets a scope count as due a little early, so a scheduler ticking
+// at exactly its interval doesn't skip every other tick.
+const dueSlack = 5 * time.Second
+
+// scopeDue reports whether a scope's interval has passed since its last
+// sync started.
+func scopeDue(store *Store, scope config.SyncScope) bool {
+	last, err := time.Parse(time.RFC3339, store.State("synced_at:"+scope.Name))
+	return err != nil || time.Since(last) >= time.Duration(scope.Interval)*time.Second-dueSlack
+}
+
+// TagIssue records that an issue is in a scope.
+func (s *Store) TagIssue(key, scope string) error {
+	_, err := s.db.Exec("INSERT OR IGNORE INTO issue_scopes (issue_key, scope) VALUES (?, ?)", key, scope)
+	return err
+}
+
+// TagScopes re-tags one issue with the scopes whose JQL includes it, for
+// changes that arrive between syncs such as webhooks. Every scope is
+// checked in one request.
+func TagScopes(client *jira.Client, store *Store, issue *jira.Issue, scopes []config.SyncScope) error {
+	jqls := make([]string, len(scopes))
+	for i, scope := range scopes {
+		jql, err := scopeJQL(client, store, scope)
+		if err != nil {
+			return err
+		}
+		jqls[i] = jql
+	}
+	matched, err := client.MatchJQL(issue.ID, jqls)
+	if err != nil {
+		return err
+	}
+	var in []string
+	for i, scope := range scopes {
+		if matched[i] {
+			in = append(in, scope.Name)
+		}
+	}
+	return store.setIssueScopes(issue.Key, in)
+}
+
+// setIssueScopes replaces one issue's scope tags.
+func (s *Store) setIssueScopes(key string, scopes []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM issue_scopes WHERE issue_key = ?", key); err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		if _, err := tx.Exec("INSERT INTO issue_scopes (issue_key, scope) VALUES (?, ?)", key, scope); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// retagScopes replaces every scope tag with the given members by scope.
+// Keys that aren't cached are skipped, and scopes left out lose their tags.
+func (s *Store) retagScopes(members map[string][]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM issue_scopes"); err != nil {
+		return err
+	}
+	for scope, keys := range members {
+		for _, key := range keys {
+			if _, err := tx.Exec(
+				"INSERT OR IGNORE INTO issue_scopes (issue_key, scope) SELECT ?, ? WHERE EXISTS (SELECT 1 FROM issues WHERE key = ?)",
+				key, scope, key,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// GetScopeIssues returns the cached issues in a scope.
+func (s *Store) GetScopeIssues(scope string) ([]jira.Issue, error) {
+	rows, err := s.db.Query(
+		"SELECT raw_json FROM issues WHERE key IN (SELECT issue_key FROM issue_scopes WHERE scope = ?) ORDER BY priority, updated_at DESC",
+		scope,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []jira.Issue
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			continue
+		}
+		var issue jira.Issue
+		if err := json.Unmarshal([]byte(raw), &issue); err != nil {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
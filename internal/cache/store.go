@@ -14,7 +14,9 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db           *sql.DB
+	customFields map[string]string // friendly name → custom field ID, for search
+	syncOverlap  time.Duration     // how far back each delta sync reaches past its cursor
 }
 
 func dbPath() (string, error) {
@@ -29,92 +31,54 @@ func dbPath() (string, error) {
 	return filepath.Join(dir, "cache.db"), nil
 }
 
+// NewStore opens the cache and brings its schema up to date.
 func NewStore() (*Store, error) {
+	store, err := Open()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := store.Migrate(); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("migrate cache: %w", err)
+	}
+	return store, nil
+}
+
+// Open opens the cache without migrating it, for `shinkansen cache`.
+func Open() (*Store, error) {
 	path, err := dbPath()
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := sql.Open("sqlite", path)
+	// Pragmas go in the DSN so every pooled connection gets them. WAL mode
+	// for concurrent reads; the daemon, webhook receiver, TUI and CLI all
+	// write, so a writer waits for the lock instead of failing with
+	// SQLITE_BUSY.
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open cache db: %w", err)
 	}
 
-	// WAL mode for better concurrent reads
-	db.Exec("PRAGMA journal_mode=WAL")
-	db.Exec("PRAGMA synchronous=NORMAL")
-
-	store := &Store{db: db}
-	if err := store.migrate(); err != nil {
-		return nil, fmt.Errorf("migrate cache: %w", err)
-	}
-	return store, nil
+	return &Store{db: db}, nil
 }
 
 func (s *Store) Close() error {
 	return s.db.Close()
 }
 
-func (s *Store) migrate() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS issues (
-		key TEXT PRIMARY KEY,
-		summary TEXT,
-		status TEXT,
-		assignee TEXT,
-		priority TEXT,
-		issue_type TEXT,
-		project_key TEXT,
-		sprint_id INTEGER,
-		updated_at TEXT,
-		raw_json TEXT
-	);
-
-	CREATE TABLE IF NOT EXISTS projects (
-		id TEXT PRIMARY KEY,
-		key TEXT,
-		name TEXT
-	);
-
-	CREATE TABLE IF NOT EXISTS sprints (
-		id INTEGER PRIMARY KEY,
-		name TEXT,
-		state TEXT,
-		board_id INTEGER,
-		start_date TEXT,
-		end_date TEXT
-	);
-
-	CREATE TABLE IF NOT EXISTS transitions (
-		issue_key TEXT,
-		transition_id TEXT,
-		name TEXT,
-		PRIMARY KEY (issue_key, transition_id)
-	);
-
-	CREATE TABLE IF NOT EXISTS sync_log (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		last_sync TEXT,
-		items_synced INTEGER,
-		duration_ms INTEGER
-	);
-
-	CREATE TABLE IF NOT EXISTS jql_filters (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		jql TEXT NOT NULL,
-		used_at TEXT NOT NULL
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_issues_status ON issues(status);
-	CREATE INDEX IF NOT EXISTS idx_issues_project ON issues(project_key);
-	CREATE INDEX IF NOT EXISTS idx_issues_assignee ON issues(assignee);
-	`
-	_, err := s.db.Exec(schema)
-	return err
-}
-
 // UpsertIssue stores or updates an issue in the cache.
+// The changelog, when present, goes to the issue_history table instead of raw_json.
 func (s *Store) UpsertIssue(issue *jira.Issue) error {
+	if issue.Changelog != nil {
+		if err := s.UpsertHistory(issue.Key, issue.Changelog.Histories); err != nil {
+			return fmt.Errorf("save history of %s: %w", issue.Key, err)
+		}
+		stripped := *issue
+		stripped.Changelog = nil
+		issue = &stripped
+	}
 	raw, _ := json.Marshal(issue)
 	assignee := ""
 	if issue.Fields.Assignee != nil {
@@ -136,7 +100,13 @@ func (s *Store) UpsertIssue(issue *jira.Issue) error {
 		assignee, issue.Fields.Priority.Name, issue.Fields.IssueType.Name,
 		issue.Fields.Project.Key, sprintID, issue.Fields.Updated, string(raw),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := indexText(s.db, issue); err != nil {
+		return err
+	}
+	return s.indexFields(issue)
 }
 
 // GetIssues returns cached issues, optionally filtered by status.
@@ -188,12 +158,23 @@ func (s *Store) GetIssue(key string) (*jira.Issue, error) {
 	return &issue, nil
 }
 
-// SearchIssues returns issues matching a text query (searches key and summary).
-func (s *Store) SearchIssues(query string) ([]jira.Issue, error) {
-	like := "%" + query + "%"
+// DeleteIssue removes an issue and everything cached for it, after it was
+// deleted, archived or moved away in Jira.
+func (s *Store) DeleteIssue(key string) error {
+	tables := []string{"issue_history", "transitions", "issue_labels", "issue_components", "issue_fix_versions", "issue_scopes", "transition_state", "issue_fts"}
+	for _, t := range tables {
+		if _, err := s.db.Exec("DELETE FROM "+t+" WHERE issue_key = ?", key); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.Exec("DELETE FROM issues WHERE key = ?", key)
+	return err
+}
+
+// GetWatchedIssues returns cached issues the current user watches.
+func (s *Store) GetWatchedIssues() ([]jira.Issue, error) {
 	rows, err := s.db.Query(
-		"SELECT raw_json FROM issues WHERE key LIKE ? OR summary LIKE ? ORDER BY updated_at DESC LIMIT 50",
-		like, like,
+		"SELECT raw_json FROM issues WHERE json_extract(raw_json, '$.fields.watches.isWatching') = 1 ORDER BY updated_at DESC",
 	)
 	if err != nil {
 		return nil, err
@@ -215,16 +196,33 @@ func (s *Store) SearchIssues(query string) ([]jira.Issue, error) {
 	return issues, nil
 }
 
-// UpsertTransitions stores transitions for an issue.
+// UpsertTransitions stores transitions for an issue, remembering the
+// status they were fetched in.
 func (s *Store) UpsertTransitions(issueKey string, transitions []jira.Transition) error {
-	s.db.Exec("DELETE FROM transitions WHERE issue_key = ?", issueKey)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM transitions WHERE issue_key = ?", issueKey); err != nil {
+		return err
+	}
 	for _, t := range transitions {
-		s.db.Exec(
+		if _, err := tx.Exec(
 			"INSERT OR REPLACE INTO transitions (issue_key, transition_id, name) VALUES (?, ?, ?)",
 			issueKey, t.ID, t.Name,
-		)
+		); err != nil {
+			return err
+		}
 	}
-	return nil
+	if _, err := tx.Exec(
+		"INSERT OR REPLACE INTO transition_state (issue_key, status, fetched_at) SELECT ?, COALESCE((SELECT status FROM issues WHERE key = ?), ''), ?",
+		issueKey, issueKey, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetTransitions returns cached transitions for an issue.
@@ -307,3 +305,16 @@ func (s *Store) RecordSync(itemsSynced int, duration time.Duration) error {
 	)
 	return err
 }
+
+// State returns a value saved with SetState, or "" when unset.
+func (s *Store) State(key string) string {
+	var value string
+	s.db.QueryRow("SELECT value FROM sync_state WHERE key = ?", key).Scan(&value)
+	return value
+}
+
+// SetState saves a small piece of sync bookkeeping.
+func (s *Store) SetState(key, value string) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO sync_state (key, value) VALUES (?, ?)", key, value)
+	return err
+}
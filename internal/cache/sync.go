@@ -1,63 +1,193 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/temujinlabs/shinkansen/internal/config"
 	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
 // SyncResult holds the outcome of a sync operation.
 type SyncResult struct {
-	ItemsSynced int
-	Duration    time.Duration
-	Err         error
+	ItemsSynced  int
+	WorklogsSent int     // queued offline worklogs submitted during this sync
+	Events       []Event // notifications recorded during this sync
+	Pruned       int     // cached issues deleted or gone out of scope
+	Rekeyed      int     // cached issues whose key changed (moved project)
+	Duration     time.Duration
+	Err          error
 }
 
-// Sync fetches updated issues from Jira and caches them.
-// Uses delta sync: only fetches issues updated since last sync.
-// projectKey scopes results to a specific project (e.g. "SCRUM").
-func Sync(client *jira.Client, store *Store, projectKey string) SyncResult {
-	start := time.Now()
+// Sync fetches updated issues for every scope and caches them.
+// Uses delta sync: each scope only fetches issues updated since the newest
+// update its previous sync saw, less an overlap window. scopes must be the
+// full configured list, since it also decides what reconciling keeps.
+func Sync(client *jira.Client, store *Store, scopes []config.SyncScope) SyncResult {
+	return syncScopes(client, store, scopes, scopes)
+}
 
-	// Build JQL scoped to the configured project.
-	// Include unresolved issues + recently resolved (last 14 days) for Done column.
-	base := "(resolution = Unresolved OR resolutiondate >= -14d)"
-	if projectKey != "" {
-		base = fmt.Sprintf("project = %s AND (resolution = Unresolved OR resolutiondate >= -14d)", projectKey)
+// SyncDue is Sync for scheduled syncs: only scopes whose interval has
+// passed are fetched.
+func SyncDue(client *jira.Client, store *Store, scopes []config.SyncScope) SyncResult {
+	var due []config.SyncScope
+	for _, scope := range scopes {
+		if scopeDue(store, scope) {
+			due = append(due, scope)
+		}
 	}
-	jql := base + " ORDER BY updated DESC"
+	return syncScopes(client, store, scopes, due)
+}
+
+// syncRun is one sync of several scopes. Scopes download concurrently and
+// save each page as it arrives; mu serializes their cache writes and the
+// totals.
+type syncRun struct {
+	client *jira.Client
+	store  *Store
+	loc    *time.Location
+	me     string
+	start  time.Time
+
+	mu     sync.Mutex
+	synced int
+	events []Event
+	errs   []error
+}
 
-	lastSync, err := store.LastSync()
-	if err == nil && !lastSync.IsZero() {
-		since := lastSync.Format("2006-01-02 15:04")
-		jql = fmt.Sprintf("%s AND updated >= '%s' ORDER BY updated DESC", base, since)
+func syncScopes(client *jira.Client, store *Store, all, due []config.SyncScope) SyncResult {
+	start := time.Now()
+
+	// Submit work logged while offline before pulling changes
+	sent, _ := FlushWorklogs(client, store)
+	if len(due) == 0 {
+		return SyncResult{WorklogsSent: sent, Duration: time.Since(start)}
 	}
 
-	issues, err := client.SearchAll(jql)
-	if err != nil {
-		return SyncResult{Err: fmt.Errorf("search: %w", err)}
+	run := &syncRun{
+		client: client,
+		store:  store,
+		loc:    jiraLocation(client, store),
+		me:     client.AccountID(),
+		start:  start,
+	}
+	var wg sync.WaitGroup
+	for _, scope := range due {
+		wg.Add(1)
+		go func(scope config.SyncScope) {
+			defer wg.Done()
+			if err := run.scope(scope); err != nil {
+				run.mu.Lock()
+				run.errs = append(run.errs, fmt.Errorf("scope %s: %w", scope.Name, err))
+				run.mu.Unlock()
+			}
+		}(scope)
 	}
+	wg.Wait()
 
-	synced := 0
-	for i := range issues {
-		if err := store.UpsertIssue(&issues[i]); err != nil {
-			continue
-		}
-		synced++
+	events := run.events
+	if err := store.AddEvents(events); err != nil {
+		events = nil
+	}
 
-		// Also cache transitions for each issue
-		transitions, err := client.GetTransitions(issues[i].Key)
-		if err == nil {
-			store.UpsertTransitions(issues[i].Key, transitions)
+	// Now and then, drop issues the delta can't tell us about
+	var pruned, rekeyed int
+	if len(run.errs) == 0 && reconcileDue(store, all) {
+		if p, r, err := reconcileScopes(client, store, all); err == nil {
+			pruned, rekeyed = p, r
 		}
 	}
 
 	duration := time.Since(start)
-	store.RecordSync(synced, duration)
+	if len(run.errs) < len(due) {
+		if err := store.RecordSync(run.synced, duration); err != nil {
+			run.errs = append(run.errs, fmt.Errorf("record sync: %w", err))
+		}
+	}
 
 	return SyncResult{
-		ItemsSynced: synced,
-		Duration:    duration,
+		ItemsSynced:  run.synced,
+		WorklogsSent: sent,
+		Events:       events,
+		Pruned:       pruned,
+		Rekeyed:      rekeyed,
+		Duration:     duration,
+		Err:          errors.Join(run.errs...),
+	}
+}
+
+// scope syncs one scope. Its first sync fetches only what lists show, and
+// the detail view loads the rest when an issue is opened; deltas are small
+// and fetch everything, since notifications need comments and descriptions.
+func (r *syncRun) scope(scope config.SyncScope) error {
+	base, err := scopeJQL(r.client, r.store, scope)
+	if err != nil {
+		return err
+	}
+	cursor := r.store.cursor(base)
+	jql, fields := base+" ORDER BY updated DESC", jira.FieldsList
+	if !cursor.IsZero() {
+		jql, fields = deltaJQL(base, cursor, r.store.overlap(), r.loc), jira.FieldsFull
+	}
+
+	// Changes to the user's issues become notification events, except on
+	// a scope's first sync, when everything would look new.
+	notify := r.me != "" && !cursor.IsZero()
+
+	newest, failed := cursor, false
+	err = r.client.SearchEach(jql, fields, func(issues []jira.Issue) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for i := range issues {
+			issue := &issues[i]
+			if t := issue.UpdatedTime(); t.After(newest) {
+				newest = t
+			}
+			// Issues in several scopes are saved once; the rest only tag it
+			if r.store.unchanged(issue) {
+				if err := r.store.TagIssue(issue.Key, scope.Name); err != nil {
+					failed = true
+				}
+				continue
+			}
+			var old *jira.Issue
+			if notify {
+				old, _ = r.store.GetIssue(issue.Key)
+				// Searches don't return the changelog, which tells whether
+				// the user made a change themselves
+				if issue.Changelog == nil && fieldsChanged(old, issue, r.me) {
+					if full, err := r.client.GetIssue(issue.Key); err == nil {
+						issue = full
+					}
+				}
+			}
+			if err := r.store.UpsertIssue(issue); err != nil {
+				failed = true
+				continue
+			}
+			if err := r.store.TagIssue(issue.Key, scope.Name); err != nil {
+				failed = true
+			}
+			r.synced++
+			if notify {
+				r.events = append(r.events, issueEvents(old, issue, r.me)...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Keep the cursor when an issue failed to save, so it is fetched again
+	if !failed && !newest.IsZero() {
+		if err := r.store.setCursor(base, newest); err != nil {
+			return err
+		}
 	}
+	return r.store.SetState("synced_at:"+scope.Name, r.start.UTC().Format(time.RFC3339))
 }
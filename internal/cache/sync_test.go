@@ -0,0 +1,538 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// fakeJira serves the endpoints a sync uses: /myself, and search pages of
+// the issues it holds, pageSize at a time. JQL matching only understands
+// "project = X".
+type fakeJira struct {
+	mu       sync.Mutex
+	timeZone string
+	issues   []fakeIssue
+	pageSize int
+	searches []fakeSearch
+	myself   int // /myself requests
+	matches  int // /jql/match requests
+}
+
+type fakeIssue struct {
+	id, key, updated string
+}
+
+type fakeSearch struct {
+	JQL    string   `json:"jql"`
+	Fields []string `json:"fields"`
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/rest/api/3/myself":
+		f.myself++
+		json.NewEncoder(w).Encode(map[string]string{"accountId": "me", "timeZone": f.timeZone})
+
+	case "/rest/api/3/search/jql":
+		var req struct {
+			fakeSearch
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.searches = append(f.searches, req.fakeSearch)
+
+		start := 0
+		fmt.Sscan(req.NextPageToken, &start)
+		end := len(f.issues)
+		if f.pageSize > 0 && start+f.pageSize < end {
+			end = start + f.pageSize
+		}
+		var page []map[string]interface{}
+		for _, i := range f.issues[start:end] {
+			page = append(page, map[string]interface{}{
+				"id":  i.id,
+				"key": i.key,
+				"fields": map[string]interface{}{
+					"summary": "Issue " + i.key,
+					"updated": i.updated,
+					"status":  map[string]string{"name": "To Do"},
+					"project": map[string]string{"key": strings.Split(i.key, "-")[0]},
+				},
+			})
+		}
+		resp := map[string]interface{}{"issues": page, "isLast": end == len(f.issues)}
+		if end < len(f.issues) {
+			resp["nextPageToken"] = fmt.Sprint(end)
+		}
+		json.NewEncoder(w).Encode(resp)
+
+	case "/rest/api/3/jql/match":
+		var req struct {
+			IssueIDs []int64  `json:"issueIds"`
+			JQLs     []string `json:"jqls"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.matches++
+		matches := []map[string]interface{}{}
+		for _, jql := range req.JQLs {
+			matched := []int64{}
+			for _, id := range req.IssueIDs {
+				for _, i := range f.issues {
+					project := strings.Split(i.key, "-")[0]
+					if i.id == fmt.Sprint(id) && strings.HasPrefix(jql, "project = "+project+" ") {
+						matched = append(matched, id)
+					}
+				}
+			}
+			matches = append(matches, map[string]interface{}{"matchedIssues": matched, "errors": []string{}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"matches": matches})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// syncSearches returns the searches that fetched issues, leaving out the
+// key-only ones reconciling makes.
+func (f *fakeJira) syncSearches() []fakeSearch {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []fakeSearch
+	for _, s := range f.searches {
+		if len(s.Fields) != 1 || s.Fields[0] != "key" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (f *fakeJira) set(issues ...fakeIssue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues = issues
+	f.searches = nil
+}
+
+// newTestSync starts a fake Jira and opens an empty cache under a temporary
+// home directory.
+func newTestSync(t *testing.T, fake *fakeJira) (*jira.Client, *Store) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return jira.NewClient(srv.URL, "me@example.com", "token"), store
+}
+
+func cachedKeys(t *testing.T, store *Store) []string {
+	t.Helper()
+	issues, err := store.GetAllIssues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, i := range issues {
+		keys = append(keys, i.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestDeltaJQL(t *testing.T) {
+	base := "project = P"
+	tests := []struct {
+		name    string
+		cursor  string
+		overlap time.Duration
+		zone    string
+		want    string
+	}{
+		{
+			name:    "utc",
+			cursor:  "2026-05-04T10:30:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "UTC",
+			want:    `project = P AND updated >= "2026/05/04 10:28" ORDER BY updated DESC`,
+		},
+		{
+			name:    "overlap crosses midnight",
+			cursor:  "2026-05-04T00:01:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "UTC",
+			want:    `project = P AND updated >= "2026/05/03 23:59" ORDER BY updated DESC`,
+		},
+		{
+			name:    "seconds are truncated, never rounded up",
+			cursor:  "2026-05-04T10:30:59.999Z",
+			overlap: 0,
+			zone:    "UTC",
+			want:    `project = P AND updated >= "2026/05/04 10:30" ORDER BY updated DESC`,
+		},
+		{
+			name:    "user timezone ahead of UTC",
+			cursor:  "2026-05-04T22:45:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "Asia/Kolkata",
+			want:    `project = P AND updated >= "2026/05/05 04:13" ORDER BY updated DESC`,
+		},
+		{
+			name:    "user timezone behind UTC",
+			cursor:  "2026-05-04T02:00:00Z",
+			overlap: 5 * time.Minute,
+			zone:    "America/Los_Angeles",
+			want:    `project = P AND updated >= "2026/05/03 18:55" ORDER BY updated DESC`,
+		},
+		{
+			name:    "cursor offset is ignored",
+			cursor:  "2026-05-04T12:30:00+02:00",
+			overlap: 0,
+			zone:    "UTC",
+			want:    `project = P AND updated >= "2026/05/04 10:30" ORDER BY updated DESC`,
+		},
+		{
+			// 07:01Z is 03:01 EDT; two minutes earlier is 01:59 EST
+			name:    "overlap spans spring forward",
+			cursor:  "2026-03-08T07:01:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "America/New_York",
+			want:    `project = P AND updated >= "2026/03/08 01:59" ORDER BY updated DESC`,
+		},
+		{
+			// 06:01Z is 01:01 EST, after the clocks went back; two minutes
+			// earlier is 01:59 EDT, the first pass through that hour
+			name:    "overlap spans fall back",
+			cursor:  "2026-11-01T06:01:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "America/New_York",
+			want:    `project = P AND updated >= "2026/11/01 01:59" ORDER BY updated DESC`,
+		},
+		{
+			name:    "user timezone in DST",
+			cursor:  "2026-07-01T12:00:00Z",
+			overlap: 2 * time.Minute,
+			zone:    "Europe/Berlin",
+			want:    `project = P AND updated >= "2026/07/01 13:58" ORDER BY updated DESC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := time.Parse(time.RFC3339Nano, tt.cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := deltaJQL(base, cursor, tt.overlap, mustLoad(t, tt.zone)); got != tt.want {
+				t.Errorf("deltaJQL() =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncOverlapSetting(t *testing.T) {
+	var s Store
+	if got := s.overlap(); got != defaultSyncOverlap {
+		t.Errorf("default overlap = %v, want %v", got, defaultSyncOverlap)
+	}
+	s.SetSyncOverlap(-time.Second)
+	if got := s.overlap(); got != defaultSyncOverlap {
+		t.Errorf("negative overlap = %v, want the default", got)
+	}
+	s.SetSyncOverlap(10 * time.Minute)
+	if got := s.overlap(); got != 10*time.Minute {
+		t.Errorf("overlap = %v, want 10m", got)
+	}
+}
+
+func TestJiraLocation(t *testing.T) {
+	fake := &fakeJira{timeZone: "Europe/Berlin"}
+	client, store := newTestSync(t, fake)
+
+	if got := jiraLocation(client, store).String(); got != "Europe/Berlin" {
+		t.Fatalf("location = %s, want Europe/Berlin", got)
+	}
+
+	// Cached for a day
+	fake.mu.Lock()
+	fake.timeZone = "Asia/Tokyo"
+	fake.mu.Unlock()
+	if got := jiraLocation(client, store).String(); got != "Europe/Berlin" {
+		t.Errorf("cached location = %s, want Europe/Berlin", got)
+	}
+	fake.mu.Lock()
+	requests := fake.myself
+	fake.mu.Unlock()
+	if requests != 1 {
+		t.Errorf("/myself requested %d times, want 1", requests)
+	}
+
+	// Refetched once stale
+	store.SetState("timezone_at", time.Now().Add(-timezoneTTL-time.Minute).UTC().Format(time.RFC3339))
+	if got := jiraLocation(client, store).String(); got != "Asia/Tokyo" {
+		t.Errorf("refreshed location = %s, want Asia/Tokyo", got)
+	}
+
+	// Unreachable: the last known zone is kept
+	offline := jira.NewClient("http://127.0.0.1:1", "", "")
+	store.SetState("timezone_at", "")
+	if got := jiraLocation(offline, store).String(); got != "Asia/Tokyo" {
+		t.Errorf("offline location = %s, want Asia/Tokyo", got)
+	}
+}
+
+func TestJiraLocationFallsBackToUTC(t *testing.T) {
+	for _, zone := range []string{"", "Not/AZone"} {
+		client, store := newTestSync(t, &fakeJira{timeZone: zone})
+		if got := jiraLocation(client, store); got != time.UTC {
+			t.Errorf("timezone %q: location = %s, want UTC", zone, got)
+		}
+	}
+}
+
+func TestSyncFirstThenDelta(t *testing.T) {
+	fake := &fakeJira{timeZone: "America/New_York", pageSize: 2}
+	client, store := newTestSync(t, fake)
+	scopes := []config.SyncScope{{Name: "p", Project: "P", Interval: 60}}
+
+	// First sync: the whole scope, list fields only, paged
+	fake.set(
+		fakeIssue{"1", "P-1", "2026-03-08T01:50:00.000-0500"},
+		fakeIssue{"2", "P-2", "2026-03-08T03:05:30.000-0400"},
+		fakeIssue{"3", "P-3", "2026-03-07T12:00:00.000-0500"},
+	)
+	res := Sync(client, store, scopes)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.ItemsSynced != 3 {
+		t.Errorf("first sync saved %d issues, want 3", res.ItemsSynced)
+	}
+	searches := fake.syncSearches()
+	if len(searches) != 2 {
+		t.Fatalf("first sync made %d searches, want 2 pages: %+v", len(searches), searches)
+	}
+	if want := "project = P AND " + recentJQL + " ORDER BY updated DESC"; searches[0].JQL != want {
+		t.Errorf("first sync JQL =\n  %s\nwant\n  %s", searches[0].JQL, want)
+	}
+	for _, f := range searches[0].Fields {
+		if f == "description" || f == "comment" {
+			t.Errorf("first sync asked for %s", f)
+		}
+	}
+	base := "project = P AND " + recentJQL
+	// The newest update, P-2 at 07:05:30Z
+	if got, want := store.cursor(base), time.Date(2026, 3, 8, 7, 5, 30, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("cursor = %v, want %v", got, want)
+	}
+
+	// Delta: since the cursor less the overlap, in the user's timezone,
+	// with details. Unchanged issues in the overlap aren't saved again.
+	fake.set(
+		fakeIssue{"2", "P-2", "2026-03-08T03:05:30.000-0400"},
+		fakeIssue{"4", "P-4", "2026-03-08T03:20:00.000-0400"},
+	)
+	res = Sync(client, store, scopes)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.ItemsSynced != 1 {
+		t.Errorf("delta saved %d issues, want 1", res.ItemsSynced)
+	}
+	searches = fake.syncSearches()
+	if len(searches) != 1 {
+		t.Fatalf("delta made %d searches, want 1: %+v", len(searches), searches)
+	}
+	if want := base + ` AND updated >= "2026/03/08 03:03" ORDER BY updated DESC`; searches[0].JQL != want {
+		t.Errorf("delta JQL =\n  %s\nwant\n  %s", searches[0].JQL, want)
+	}
+	hasDescription := false
+	for _, f := range searches[0].Fields {
+		hasDescription = hasDescription || f == "description"
+	}
+	if !hasDescription {
+		t.Errorf("delta didn't ask for descriptions: %v", searches[0].Fields)
+	}
+	if got, want := store.cursor(base), time.Date(2026, 3, 8, 7, 20, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("cursor = %v, want %v", got, want)
+	}
+	if got, want := strings.Join(cachedKeys(t, store), " "), "P-1 P-2 P-3 P-4"; got != want {
+		t.Errorf("cached %s, want %s", got, want)
+	}
+	scoped, err := store.GetScopeIssues("p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scoped) != 4 {
+		t.Errorf("%d issues tagged with the scope, want 4", len(scoped))
+	}
+
+	// Nothing new: the cursor stays put
+	res = Sync(client, store, scopes)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.ItemsSynced != 0 {
+		t.Errorf("empty delta saved %d issues", res.ItemsSynced)
+	}
+	if got, want := store.cursor(base), time.Date(2026, 3, 8, 7, 20, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("cursor moved to %v, want %v", got, want)
+	}
+}
+
+func TestSyncCursorsPerScope(t *testing.T) {
+	fake := &fakeJira{timeZone: "UTC"}
+	client, store := newTestSync(t, fake)
+
+	fake.set(fakeIssue{"1", "P-1", "2026-05-04T10:00:00.000+0000"})
+	if res := Sync(client, store, []config.SyncScope{{Name: "p", Project: "P"}}); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	// A scope added later starts with a full sync of its own
+	fake.set(fakeIssue{"1", "P-1", "2026-05-04T10:00:00.000+0000"})
+	scopes := []config.SyncScope{{Name: "p", Project: "P"}, {Name: "mine", JQL: "assignee = currentUser() ORDER BY rank"}}
+	if res := Sync(client, store, scopes); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	var delta, full int
+	for _, s := range fake.syncSearches() {
+		switch {
+		case strings.HasPrefix(s.JQL, "project = P") && strings.Contains(s.JQL, "updated >="):
+			delta++
+		case strings.HasPrefix(s.JQL, "(assignee = currentUser()) AND") && !strings.Contains(s.JQL, "updated >="):
+			full++
+		default:
+			t.Errorf("unexpected search %s", s.JQL)
+		}
+	}
+	if delta != 1 || full != 1 {
+		t.Errorf("%d delta and %d full searches, want one each", delta, full)
+	}
+}
+
+func TestSyncReconcile(t *testing.T) {
+	fake := &fakeJira{timeZone: "UTC"}
+	client, store := newTestSync(t, fake)
+	scopes := []config.SyncScope{{Name: "p", Project: "P"}}
+
+	fake.set(
+		fakeIssue{"1", "P-1", "2026-05-04T10:00:00.000+0000"},
+		fakeIssue{"2", "P-2", "2026-05-04T10:01:00.000+0000"},
+		fakeIssue{"3", "P-3", "2026-05-04T10:02:00.000+0000"},
+	)
+	if res := Sync(client, store, scopes); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if err := store.StartTimer("P-3", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// P-1 is deleted and P-3 moved to Q-9: a delta can't see either
+	fake.set(
+		fakeIssue{"2", "P-2", "2026-05-04T10:01:00.000+0000"},
+		fakeIssue{"3", "Q-9", "2026-05-04T10:02:00.000+0000"},
+	)
+	store.SetState("reconciled_at", time.Now().Add(-reconcileInterval-time.Minute).UTC().Format(time.RFC3339))
+	res := Sync(client, store, scopes)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if res.Pruned != 1 || res.Rekeyed != 1 {
+		t.Errorf("pruned %d and rekeyed %d, want 1 and 1", res.Pruned, res.Rekeyed)
+	}
+	if got, want := strings.Join(cachedKeys(t, store), " "), "P-2 Q-9"; got != want {
+		t.Errorf("cached %s, want %s", got, want)
+	}
+	timer, err := store.ActiveTimer()
+	if err != nil || timer == nil || timer.IssueKey != "Q-9" {
+		t.Errorf("timer = %+v, %v; want it on Q-9", timer, err)
+	}
+
+	// Not due again within the interval
+	fake.set()
+	if res := Sync(client, store, scopes); res.Pruned != 0 {
+		t.Errorf("reconciled again right away, pruning %d", res.Pruned)
+	}
+}
+
+func TestReconcileKeepsCacheOnEmptySearch(t *testing.T) {
+	fake := &fakeJira{timeZone: "UTC"}
+	client, store := newTestSync(t, fake)
+
+	fake.set(fakeIssue{"1", "P-1", "2026-05-04T10:00:00.000+0000"})
+	if res := Sync(client, store, []config.SyncScope{{Name: "p", Project: "P"}}); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	fake.set()
+	pruned, _, err := Reconcile(client, store, map[string]string{"p": "project = P"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 0 || len(cachedKeys(t, store)) != 1 {
+		t.Errorf("pruned %d issues after an empty search, want none", pruned)
+	}
+}
+
+func TestTagScopes(t *testing.T) {
+	fake := &fakeJira{timeZone: "UTC"}
+	client, store := newTestSync(t, fake)
+
+	fake.set(fakeIssue{"1", "P-1", "2026-05-04T10:00:00.000+0000"})
+	scopes := []config.SyncScope{{Name: "p", Project: "P"}, {Name: "q", Project: "Q"}, {Name: "mine", JQL: "assignee = currentUser()"}}
+	if res := Sync(client, store, scopes); res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	issue, err := store.GetIssue("P-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := TagScopes(client, store, issue, scopes); err != nil {
+		t.Fatal(err)
+	}
+	if fake.matches != 1 {
+		t.Errorf("%d match requests for %d scopes, want one", fake.matches, len(scopes))
+	}
+	for _, scope := range scopes {
+		issues, err := store.GetScopeIssues(scope.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := scope.Name == "p"; (len(issues) == 1) != want {
+			t.Errorf("scope %s has %d issues, want P-1 in it: %v", scope.Name, len(issues), want)
+		}
+	}
+}
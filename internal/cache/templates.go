@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// SubtaskType returns the name of the project's sub-task issue type.
+func SubtaskType(client *jira.Client, store *Store, projectKey string) (string, error) {
+	types, err := CreateIssueTypes(client, store, projectKey)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range types {
+		if t.Subtask {
+			return t.Name, nil
+		}
+	}
+	return "", fmt.Errorf("project %s has no sub-task issue type", projectKey)
+}
+
+// CreateFromTemplate creates an issue in projectKey from t, filling its
+// placeholders from values, followed by the template's subtasks. It returns
+// the new issue and the keys of the subtasks created.
+func CreateFromTemplate(client *jira.Client, store *Store, t *config.Template, projectKey string, values map[string]string) (*jira.Issue, []string, error) {
+	issueType := t.Type
+	if issueType == "" {
+		issueType = "Task"
+	}
+	extra := map[string]interface{}{}
+	if len(t.Labels) > 0 {
+		extra["labels"] = t.Labels
+	}
+	if len(t.Components) > 0 {
+		extra["components"] = jira.NameRefs(t.Components)
+	}
+
+	issue, err := client.CreateIssueWithDetails(projectKey, config.Expand(t.Summary, values), issueType,
+		t.Priority, config.Expand(t.Description, values), extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys, err := CreateTemplateSubtasks(client, store, t, projectKey, issue.Key, values)
+	return issue, keys, err
+}
+
+// CreateTemplateSubtasks creates the subtasks of t under parentKey. It stops
+// at the first failure, returning the keys created so far.
+func CreateTemplateSubtasks(client *jira.Client, store *Store, t *config.Template, projectKey, parentKey string, values map[string]string) ([]string, error) {
+	var keys []string
+	var defaultType string
+	for _, st := range t.Subtasks {
+		issueType := st.Type
+		if issueType == "" {
+			if defaultType == "" {
+				var err error
+				if defaultType, err = SubtaskType(client, store, projectKey); err != nil {
+					return keys, err
+				}
+			}
+			issueType = defaultType
+		}
+		extra := map[string]interface{}{"parent": map[string]string{"key": parentKey}}
+		sub, err := client.CreateIssueWithDetails(projectKey, config.Expand(st.Summary, values), issueType,
+			"", config.Expand(st.Description, values), extra)
+		if err != nil {
+			return keys, fmt.Errorf("subtask %q: %w", st.Summary, err)
+		}
+		keys = append(keys, sub.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// Timer tracks time spent on an issue. Only one timer exists at a time; it is
+// persisted so it survives restarts and is shared by the TUI and the CLI.
+type Timer struct {
+	IssueKey     string
+	StartedAt    time.Time     // first start, used as the worklog start time
+	RunningSince time.Time     // zero while paused
+	Accumulated  time.Duration // time from earlier running spans
+	LastSeen     time.Time     // last heartbeat from a running TUI
+}
+
+// Running reports whether the timer is currently counting.
+func (t *Timer) Running() bool {
+	return !t.RunningSince.IsZero()
+}
+
+// Elapsed returns the total counted time as of now.
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	d := t.Accumulated
+	if t.Running() && now.After(t.RunningSince) {
+		d += now.Sub(t.RunningSince)
+	}
+	return d
+}
+
+// RoundDuration rounds d to the nearest multiple of increment, never going
+// below one increment so a short session still produces a worklog.
+func RoundDuration(d, increment time.Duration) time.Duration {
+	if increment <= 0 {
+		return d.Round(time.Minute)
+	}
+	r := d.Round(increment)
+	if r < increment {
+		r = increment
+	}
+	return r
+}
+
+// ActiveTimer returns the current timer, or nil if none is set.
+func (s *Store) ActiveTimer() (*Timer, error) {
+	var t Timer
+	var started, running, lastSeen string
+	var accMs int64
+	err := s.db.QueryRow(
+		"SELECT issue_key, started_at, running_since, accumulated_ms, last_seen FROM timers LIMIT 1",
+	).Scan(&t.IssueKey, &started, &running, &accMs, &lastSeen)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.StartedAt, _ = time.Parse(time.RFC3339, started)
+	if running != "" {
+		t.RunningSince, _ = time.Parse(time.RFC3339, running)
+	}
+	t.LastSeen, _ = time.Parse(time.RFC3339, lastSeen)
+	t.Accumulated = time.Duration(accMs) * time.Millisecond
+	return &t, nil
+}
+
+// StartTimer starts a timer on an issue. It fails if a timer already exists.
+func (s *Store) StartTimer(issueKey string, now time.Time) error {
+	if t, err := s.ActiveTimer(); err != nil {
+		return err
+	} else if t != nil {
+		return fmt.Errorf("timer already running on %s", t.IssueKey)
+	}
+	ts := now.UTC().Format(time.RFC3339)
+	_, err := s.db.Exec(
+		"INSERT INTO timers (issue_key, started_at, running_since, accumulated_ms, last_seen) VALUES (?, ?, ?, 0, ?)",
+		issueKey, ts, ts, ts,
+	)
+	return err
+}
+
+// PauseTimer stops counting, keeping the time counted so far. at is normally
+// now; idle detection passes the last heartbeat to drop the idle gap.
+func (s *Store) PauseTimer(at time.Time) error {
+	t, err := s.ActiveTimer()
+	if err != nil || t == nil || !t.Running() {
+		return err
+	}
+	_, err = s.db.Exec(
+		"UPDATE timers SET running_since = '', accumulated_ms = ? WHERE issue_key = ?",
+		t.Elapsed(at).Milliseconds(), t.IssueKey,
+	)
+	return err
+}
+
+// ResumeTimer continues a paused timer.
+func (s *Store) ResumeTimer(now time.Time) error {
+	t, err := s.ActiveTimer()
+	if err != nil || t == nil || t.Running() {
+		return err
+	}
+	ts := now.UTC().Format(time.RFC3339)
+	_, err = s.db.Exec("UPDATE timers SET running_since = ?, last_seen = ? WHERE issue_key = ?", ts, ts, t.IssueKey)
+	return err
+}
+
+// StopTimer removes the timer and returns its final state.
+func (s *Store) StopTimer(now time.Time) (*Timer, error) {
+	t, err := s.ActiveTimer()
+	if err != nil || t == nil {
+		return nil, err
+	}
+	t.Accumulated = t.Elapsed(now)
+	t.RunningSince = time.Time{}
+	if _, err := s.db.Exec("DELETE FROM timers WHERE issue_key = ?", t.IssueKey); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// TouchTimer records a heartbeat for idle detection.
+func (s *Store) TouchTimer(now time.Time) error {
+	_, err := s.db.Exec("UPDATE timers SET last_seen = ?", now.UTC().Format(time.RFC3339))
+	return err
+}
+
+// PendingWorklog is a worklog that could not be sent while offline.
+type PendingWorklog struct {
+	ID        int64
+	IssueKey  string
+	Input     jira.WorklogInput
+	LastError string
+}
+
+// QueueWorklog stores a worklog to be submitted on the next successful sync.
+func (s *Store) QueueWorklog(issueKey string, in jira.WorklogInput) error {
+	started := in.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+	_, err := s.db.Exec(
+		"INSERT INTO pending_worklogs (issue_key, time_spent, started, comment, adjust_estimate, new_estimate, reduce_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		issueKey, in.TimeSpent, started.UTC().Format(time.RFC3339), in.Comment, in.AdjustEstimate, in.NewEstimate, in.ReduceBy,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// PendingWorklogs returns queued worklogs, oldest first, including ones Jira
+// rejected (LastError set) so they can be reported.
+func (s *Store) PendingWorklogs() ([]PendingWorklog, error) {
+	rows, err := s.db.Query("SELECT id, issue_key, time_spent, started, comment, adjust_estimate, new_estimate, reduce_by, last_error FROM pending_worklogs ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []PendingWorklog
+	for rows.Next() {
+		var p PendingWorklog
+		var started string
+		if err := rows.Scan(&p.ID, &p.IssueKey, &p.Input.TimeSpent, &started, &p.Input.Comment, &p.Input.AdjustEstimate, &p.Input.NewEstimate, &p.Input.ReduceBy, &p.LastError); err != nil {
+			continue
+		}
+		p.Input.Started, _ = time.Parse(time.RFC3339, started)
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
+
+// RetryWorklog clears the error of a queued worklog Jira rejected, so the
+// next flush sends it again.
+func (s *Store) RetryWorklog(id int64) error {
+	res, err := s.db.Exec("UPDATE pending_worklogs SET last_error = '' WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return queuedWorklogFound(res, id)
+}
+
+// DropWorklog removes a queued worklog without sending it.
+func (s *Store) DropWorklog(id int64) error {
+	res, err := s.db.Exec("DELETE FROM pending_worklogs WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return queuedWorklogFound(res, id)
+}
+
+func queuedWorklogFound(res sql.Result, id int64) error {
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no queued worklog #%d", id)
+	}
+	return nil
+}
+
+// RejectedWorklogs counts queued worklogs Jira rejected, which wait for
+// `shinkansen timer queue retry` or `drop`.
+func (s *Store) RejectedWorklogs() (int, error) {
+	var n int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM pending_worklogs WHERE last_error != ''").Scan(&n)
+	return n, err
+}
+
+// FlushWorklogs submits queued worklogs. Entries Jira rejects are kept with
+// their error instead of being retried forever; it stops at the first
+// failure that isn't about the entry (offline, or not authenticated) since
+// the rest would fail the same way.
+func FlushWorklogs(client *jira.Client, store *Store) (int, error) {
+	pending, err := store.PendingWorklogs()
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, p := range pending {
+		if p.LastError != "" {
+			continue
+		}
+		err := client.LogWork(p.IssueKey, p.Input)
+		var apiErr *jira.APIError
+		if err != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode == http.StatusUnauthorized) {
+			return sent, err
+		}
+		if err != nil {
+			if _, err := store.db.Exec("UPDATE pending_worklogs SET last_error = ? WHERE id = ?", err.Error(), p.ID); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		// A worklog left queued after it was sent would be logged twice
+		if _, err := store.db.Exec("DELETE FROM pending_worklogs WHERE id = ?", p.ID); err != nil {
+			return sent, fmt.Errorf("worklog on %s sent but still queued: %w", p.IssueKey, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
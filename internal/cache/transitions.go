@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// transitionTTL is how long cached transitions are trusted. Workflows
+// rarely change; a status change invalidates them right away.
+const transitionTTL = 6 * time.Hour
+
+// Transition warm-up: at most warmWorkers requests at a time, for up to
+// warmLimit issues per run.
+const (
+	warmWorkers = 4
+	warmLimit   = 50
+)
+
+// freshSince is the oldest fetch time still within transitionTTL.
+func freshSince() string {
+	return time.Now().Add(-transitionTTL).UTC().Format(time.RFC3339)
+}
+
+// FreshTransitions returns an issue's cached transitions if they were
+// fetched within transitionTTL and the issue hasn't changed status since.
+func (s *Store) FreshTransitions(issueKey string) ([]jira.Transition, bool) {
+	var n int
+	s.db.QueryRow(`
+		SELECT COUNT(*) FROM transition_state t LEFT JOIN issues i ON i.key = t.issue_key
+		WHERE t.issue_key = ? AND t.status = COALESCE(i.status, '') AND t.fetched_at >= ?`,
+		issueKey, freshSince(),
+	).Scan(&n)
+	if n == 0 {
+		return nil, false
+	}
+	transitions, err := s.GetTransitions(issueKey)
+	return transitions, err == nil
+}
+
+// InvalidateTransitions forgets when an issue's transitions were fetched,
+// after it was moved, so they are fetched again next time.
+func (s *Store) InvalidateTransitions(issueKey string) error {
+	_, err := s.db.Exec("DELETE FROM transition_state WHERE issue_key = ?", issueKey)
+	return err
+}
+
+// Transitions returns the transitions available on an issue: from the
+// cache when fresh, else from Jira, caching them. Offline, stale cached
+// transitions are better than none.
+func Transitions(client *jira.Client, store *Store, issueKey string) ([]jira.Transition, error) {
+	if transitions, ok := store.FreshTransitions(issueKey); ok {
+		return transitions, nil
+	}
+	transitions, err := client.GetTransitions(issueKey)
+	if err != nil {
+		if cached, _ := store.GetTransitions(issueKey); len(cached) > 0 {
+			return cached, nil
+		}
+		return nil, err
+	}
+	store.UpsertTransitions(issueKey, transitions)
+	return transitions, nil
+}
+
+// staleTransitionKeys returns up to limit issues assigned to accountID whose
+// transitions aren't fresh, most recently updated first.
+func (s *Store) staleTransitionKeys(accountID string, limit int) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT i.key FROM issues i LEFT JOIN transition_state t ON t.issue_key = i.key
+		WHERE json_extract(i.raw_json, '$.fields.assignee.accountId') = ?
+		  AND (t.issue_key IS NULL OR t.status != i.status OR t.fetched_at < ?)
+		ORDER BY i.updated_at DESC LIMIT ?`,
+		accountID, freshSince(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// WarmTransitions fetches transitions in the background for the user's own
+// issues that lack fresh ones, since those are the ones likely to be moved
+// next, and returns how many it cached. Requests run on a small worker
+// pool; results are saved from the calling goroutine.
+func WarmTransitions(client *jira.Client, store *Store) int {
+	me := client.AccountID()
+	if me == "" {
+		return 0
+	}
+	keys, err := store.staleTransitionKeys(me, warmLimit)
+	if err != nil || len(keys) == 0 {
+		return 0
+	}
+
+	type fetched struct {
+		key         string
+		transitions []jira.Transition
+		err         error
+	}
+	jobs := make(chan string)
+	results := make(chan fetched)
+	for i := 0; i < min(warmWorkers, len(keys)); i++ {
+		go func() {
+			for key := range jobs {
+				transitions, err := client.GetTransitions(key)
+				results <- fetched{key, transitions, err}
+			}
+		}()
+	}
+	go func() {
+		for _, key := range keys {
+			jobs <- key
+		}
+		close(jobs)
+	}()
+
+	warmed := 0
+	for range keys {
+		r := <-results
+		if r.err == nil && store.UpsertTransitions(r.key, r.transitions) == nil {
+			warmed++
+		}
+	}
+	return warmed
+}
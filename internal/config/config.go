@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 type Config struct {
@@ -16,6 +18,34 @@ type Config struct {
 	DefaultBoard   int    `json:"default_board,omitempty"`
 	SyncInterval   int    `json:"sync_interval,omitempty"` // seconds, default 60
 
+	// Named sets of issues to sync, default the default project
+	SyncScopes []SyncScope `json:"sync_scopes,omitempty"`
+
+	// Seconds each delta sync reaches back past the newest update it saw, default 120
+	SyncOverlapSeconds int `json:"sync_overlap_seconds,omitempty"`
+
+	// Timer settings
+	TimerRoundMinutes int `json:"timer_round_minutes,omitempty"` // worklog rounding increment, default 15
+	TimerIdleMinutes  int `json:"timer_idle_minutes,omitempty"`  // pause after this long without activity, default 10
+
+	// Where attachments are downloaded, default ~/Downloads
+	AttachmentDir string `json:"attachment_dir,omitempty"`
+
+	// Timesheet: weekdays with less logged time than this are highlighted (0 = off)
+	DailyTargetHours float64 `json:"daily_target_hours,omitempty"`
+
+	// Custom fields by friendly name, e.g. {"story_points": "customfield_10016"}.
+	// `shinkansen fields` lists the IDs. Mapped fields are synced and shown.
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	// Custom fields (friendly names) shown as extra issue list columns
+	ListColumns []string `json:"list_columns,omitempty"`
+
+	// Desktop notifications for changes to your issues (off when unset)
+	Notify *NotifyConfig `json:"notify,omitempty"`
+
+	// Shared secret for `shinkansen webhook serve`
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+
 	// OAuth 2.0 (3LO) fields
 	AuthMethod    string `json:"auth_method,omitempty"`     // "api-token" or "oauth"
 	OAuthClientID string `json:"oauth_client_id,omitempty"` // from developer.atlassian.com
@@ -44,6 +74,29 @@ func (c *Config) BrowseURL(issueKey string) string {
 	return c.JiraURL + "/browse/" + issueKey
 }
 
+// DownloadDir returns the directory attachments are saved to, expanding "~".
+func (c *Config) DownloadDir() string {
+	home, _ := os.UserHomeDir()
+	dir := c.AttachmentDir
+	if dir == "" {
+		return filepath.Join(home, "Downloads")
+	}
+	if strings.HasPrefix(dir, "~/") {
+		dir = filepath.Join(home, dir[2:])
+	}
+	return dir
+}
+
+// CustomFieldNames returns the friendly names of mapped custom fields, sorted.
+func (c *Config) CustomFieldNames() []string {
+	names := make([]string, 0, len(c.CustomFields))
+	for name := range c.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func configDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -69,7 +122,9 @@ func Load() (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &Config{SyncInterval: 60}, nil
+			cfg := &Config{SyncInterval: 60}
+			cfg.applyDefaults()
+			return cfg, nil
 		}
 		return nil, fmt.Errorf("read config: %w", err)
 	}
@@ -79,12 +134,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
-	if cfg.SyncInterval == 0 {
-		cfg.SyncInterval = 60
+	cfg.applyDefaults()
+	if err := cfg.ValidateScopes(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 	return &cfg, nil
 }
 
+// applyDefaults fills in settings that were left unset in config.json.
+func (c *Config) applyDefaults() {
+	if c.SyncInterval == 0 {
+		c.SyncInterval = 60
+	}
+	if c.TimerRoundMinutes == 0 {
+		c.TimerRoundMinutes = 15
+	}
+	if c.TimerIdleMinutes == 0 {
+		c.TimerIdleMinutes = 10
+	}
+}
+
 func Save(cfg *Config) error {
 	dir, err := configDir()
 	if err != nil {
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NotifyConfig controls desktop notifications for the events a sync records
+// (see the Notifications view). Events are always recorded; these settings
+// only decide which of them pop up.
+type NotifyConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Event kinds to notify about: mention, assigned, status, comment,
+	// priority. Default: mention, assigned, status.
+	Events []string `json:"events,omitempty"`
+
+	// Command run through sh for each notification instead of notify-send
+	// or D-Bus. It gets SHINKANSEN_TITLE, SHINKANSEN_BODY, SHINKANSEN_ISSUE,
+	// SHINKANSEN_KIND and SHINKANSEN_URL in its environment.
+	Command string `json:"command,omitempty"`
+
+	// Local time range without popups, e.g. "22:00-08:00"
+	QuietHours string `json:"quiet_hours,omitempty"`
+
+	// Popups per sync (default 3) and per hour (default 20); events over the
+	// limit are summed up in one popup
+	MaxPerSync int `json:"max_per_sync,omitempty"`
+	MaxPerHour int `json:"max_per_hour,omitempty"`
+}
+
+var defaultNotifyEvents = []string{"mention", "assigned", "status"}
+
+// Wants reports whether events of a kind should pop up.
+func (n *NotifyConfig) Wants(kind string) bool {
+	events := n.Events
+	if len(events) == 0 {
+		events = defaultNotifyEvents
+	}
+	for _, e := range events {
+		if strings.EqualFold(e, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits returns the popups allowed per sync and per hour.
+func (n *NotifyConfig) Limits() (perSync, perHour int) {
+	perSync, perHour = n.MaxPerSync, n.MaxPerHour
+	if perSync <= 0 {
+		perSync = 3
+	}
+	if perHour <= 0 {
+		perHour = 20
+	}
+	return perSync, perHour
+}
+
+// InQuietHours reports whether t falls in the quiet hours. Ranges may wrap
+// past midnight.
+func (n *NotifyConfig) InQuietHours(t time.Time) bool {
+	from, to, err := n.quietRange()
+	if err != nil || from == to {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	if from < to {
+		return m >= from && m < to
+	}
+	return m >= from || m < to
+}
+
+// quietRange parses QuietHours into minutes after midnight.
+func (n *NotifyConfig) quietRange() (from, to int, err error) {
+	if n.QuietHours == "" {
+		return 0, 0, nil
+	}
+	start, end, ok := strings.Cut(n.QuietHours, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("quiet_hours: expected HH:MM-HH:MM, got %q", n.QuietHours)
+	}
+	minutes := func(s string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, fmt.Errorf("quiet_hours: bad time %q", s)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if from, err = minutes(start); err != nil {
+		return 0, 0, err
+	}
+	if to, err = minutes(end); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// Validate checks the settings that can be malformed.
+func (n *NotifyConfig) Validate() error {
+	_, _, err := n.quietRange()
+	return err
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// WatchingScope is the scope always synced for the Watching view.
+const WatchingScope = "watching"
+
+// SyncScope is a named set of issues kept in the cache: a project, a board
+// (through its saved filter) or any JQL, e.g. "my team's open bugs". Set
+// exactly one of Project, Board and JQL.
+type SyncScope struct {
+	Name    string `json:"name"`
+	Project string `json:"project,omitempty"`
+	Board   int    `json:"board,omitempty"`
+	JQL     string `json:"jql,omitempty"`
+
+	// Seconds between syncs of this scope, default sync_interval
+	Interval int `json:"interval,omitempty"`
+}
+
+// Scopes returns the scopes to sync: the configured ones, or the default
+// project (or everything) when there are none, plus the issues the user
+// watches. Intervals left unset are filled in from SyncInterval.
+func (c *Config) Scopes() []SyncScope {
+	scopes := append([]SyncScope(nil), c.SyncScopes...)
+	switch {
+	case len(scopes) > 0:
+	case c.DefaultProject != "":
+		scopes = append(scopes, SyncScope{Name: c.DefaultProject, Project: c.DefaultProject})
+	default:
+		scopes = append(scopes, SyncScope{Name: "all"}) // every issue the user can see
+	}
+	watching := false
+	for _, s := range scopes {
+		watching = watching || s.Name == WatchingScope
+	}
+	if !watching {
+		scopes = append(scopes, SyncScope{Name: WatchingScope, JQL: "watcher = currentUser()"})
+	}
+	for i := range scopes {
+		if scopes[i].Interval <= 0 {
+			scopes[i].Interval = c.SyncInterval
+		}
+	}
+	return scopes
+}
+
+// SwitchProject makes key the default project for this run and returns
+// the name of the scope syncing it. With sync_scopes set and none for the
+// project, a scope is added for it. changed reports whether the scopes
+// changed and need a sync. Nothing is saved.
+func (c *Config) SwitchProject(key string) (scope string, changed bool) {
+	if len(c.SyncScopes) == 0 {
+		changed = c.DefaultProject != key
+		c.DefaultProject = key
+		return key, changed
+	}
+	c.DefaultProject = key
+	taken := map[string]bool{}
+	for _, s := range c.SyncScopes {
+		if s.Project == key {
+			return s.Name, false
+		}
+		taken[s.Name] = true
+	}
+	name := key
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d", key, n)
+	}
+	c.SyncScopes = append(c.SyncScopes, SyncScope{Name: name, Project: key})
+	return name, true
+}
+
+// MinSyncInterval returns the shortest scope interval, which is how often
+// a scheduler has to wake up.
+func (c *Config) MinSyncInterval() time.Duration {
+	shortest := 0
+	for _, s := range c.Scopes() {
+		if shortest == 0 || s.Interval < shortest {
+			shortest = s.Interval
+		}
+	}
+	if shortest <= 0 {
+		return time.Minute
+	}
+	return time.Duration(shortest) * time.Second
+}
+
+// ValidateScopes checks that scopes have unique names and one source each.
+func (c *Config) ValidateScopes() error {
+	seen := map[string]bool{}
+	for i, s := range c.SyncScopes {
+		if s.Name == "" {
+			return fmt.Errorf("sync_scopes[%d]: name is required", i)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("sync_scopes: duplicate name %q", s.Name)
+		}
+		seen[s.Name] = true
+
+		sources := 0
+		if s.Project != "" {
+			sources++
+		}
+		if s.Board > 0 {
+			sources++
+		}
+		if s.JQL != "" {
+			sources++
+		}
+		if sources != 1 {
+			return fmt.Errorf("sync scope %q: set exactly one of project, board and jql", s.Name)
+		}
+	}
+	return nil
+}
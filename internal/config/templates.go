@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template describes a recurring issue shape, read from
+// ~/.config/shinkansen/templates/<name>.yaml. Text fields may contain
+// {{placeholder}} words that are asked for when the template is used.
+type Template struct {
+	Name        string            `yaml:"-"` // file name without extension
+	Project     string            `yaml:"project"`
+	Type        string            `yaml:"type"`
+	Priority    string            `yaml:"priority"`
+	Summary     string            `yaml:"summary"`
+	Labels      []string          `yaml:"labels"`
+	Components  []string          `yaml:"components"`
+	Description string            `yaml:"description"` // Markdown
+	Subtasks    []TemplateSubtask `yaml:"subtasks"`
+}
+
+// TemplateSubtask is a child issue created under the templated issue.
+type TemplateSubtask struct {
+	Summary     string `yaml:"summary"`
+	Type        string `yaml:"type"` // default: the project's sub-task type
+	Description string `yaml:"description"`
+}
+
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// builtinPlaceholders are filled in without asking.
+var builtinPlaceholders = map[string]func() string{
+	"date": func() string { return time.Now().Format("2006-01-02") },
+}
+
+// TemplateDir returns the directory templates are read from.
+func TemplateDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// LoadTemplates reads all templates, sorted by name. A missing directory
+// means no templates.
+func LoadTemplates() ([]Template, error) {
+	dir, err := TemplateDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+
+	var templates []Template
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		t, err := readTemplate(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadTemplate reads the template with the given name.
+func LoadTemplate(name string) (*Template, error) {
+	dir, err := TemplateDir()
+	if err != nil {
+		return nil, err
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return readTemplate(path)
+		}
+	}
+	return nil, fmt.Errorf("no template %q in %s", name, dir)
+}
+
+func readTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", filepath.Base(path), err)
+	}
+	t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return &t, nil
+}
+
+// Placeholders returns the placeholder names used anywhere in the template,
+// in order of first use. Built-in placeholders such as {{date}} are left out.
+func (t *Template) Placeholders() []string {
+	texts := []string{t.Summary, t.Description}
+	for _, s := range t.Subtasks {
+		texts = append(texts, s.Summary, s.Description)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, m := range placeholderRe.FindAllStringSubmatch(text, -1) {
+			name := m[1]
+			if seen[name] || builtinPlaceholders[name] != nil {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Expand replaces placeholders in text with their values. Placeholders
+// without a value are replaced with an empty string.
+func Expand(text string, values map[string]string) string {
+	return placeholderRe.ReplaceAllStringFunc(text, func(m string) string {
+		name := placeholderRe.FindStringSubmatch(m)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		if f := builtinPlaceholders[name]; f != nil {
+			return f()
+		}
+		return ""
+	})
+}
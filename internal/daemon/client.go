@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+)
+
+// Client is a connection to a running daemon for status and sync requests.
+// Requests from several goroutines are sent one at a time.
+type Client struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex
+}
+
+// Dial connects to the daemon. It fails quickly when none is running.
+func Dial() (*Client, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, 500*time.Millisecond)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// callTimeout bounds a request, so a hung daemon doesn't block the caller
+// forever. A sync of every scope is the slowest request.
+const callTimeout = 2 * time.Minute
+
+// call sends a request and reads its reply.
+func (c *Client) call(req Request) (*Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetDeadline(time.Now().Add(callTimeout))
+	if err := json.NewEncoder(c.conn).Encode(req); err != nil {
+		return nil, err
+	}
+	line, err := c.r.ReadBytes('\n')
+	if err != nil {
+		// A late reply would be read as the next request's; later calls
+		// fail instead
+		c.conn.Close()
+		return nil, err
+	}
+	var msg Message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return nil, err
+	}
+	if msg.Type == MsgError {
+		return nil, fmt.Errorf("daemon: %s", msg.Error)
+	}
+	return &msg, nil
+}
+
+// Status asks for the daemon's state.
+func (c *Client) Status() (*Status, error) {
+	msg, err := c.call(Request{Cmd: CmdStatus})
+	if err != nil {
+		return nil, err
+	}
+	return msg.Status, nil
+}
+
+// Sync asks the daemon to sync now and waits for the result.
+func (c *Client) Sync() (*Report, error) {
+	msg, err := c.call(Request{Cmd: CmdSync})
+	if err != nil {
+		return nil, err
+	}
+	return msg.Sync, nil
+}
+
+// SyncProject switches the daemon's default project, as the TUI's project
+// picker does, then syncs like Sync.
+func (c *Client) SyncProject(project string) (*Report, error) {
+	msg, err := c.call(Request{Cmd: CmdSync, Project: project})
+	if err != nil {
+		return nil, err
+	}
+	return msg.Sync, nil
+}
+
+// Changed tells the daemon's subscribers that issues were updated in the
+// cache.
+func (c *Client) Changed(keys []string, events []cache.Event) error {
+	_, err := c.call(Request{Cmd: CmdChanged, Keys: keys, Events: events})
+	return err
+}
+
+// Subscribe opens a connection that receives a message for every sync the
+// daemon runs. The channel is closed when the daemon goes away.
+func Subscribe() (<-chan Message, error) {
+	c, err := Dial()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(c.conn).Encode(Request{Cmd: CmdSubscribe}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	ch := make(chan Message)
+	go func() {
+		defer close(ch)
+		defer c.Close()
+		for {
+			line, err := c.r.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var msg Message
+			if json.Unmarshal(line, &msg) == nil {
+				ch <- msg
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,116 @@
+// Package daemon runs syncing in one background process and lets the TUI
+// and CLI talk to it over a Unix socket, so several open terminals share
+// one polling loop instead of each hitting Jira.
+//
+// The protocol is JSON lines: a client writes a Request per line and reads
+// Messages. "status" and "sync" get one reply each; after "subscribe" the
+// connection receives a "synced" message for every sync, and a "changed"
+// message when another process (the webhook receiver) updated issues, until
+// it closes.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+)
+
+// Request commands.
+const (
+	CmdStatus    = "status"
+	CmdSync      = "sync"
+	CmdSubscribe = "subscribe"
+	CmdChanged   = "changed" // announce issues updated in the cache
+)
+
+// Message types.
+const (
+	MsgStatus  = "status"
+	MsgSynced  = "synced"
+	MsgChanged = "changed"
+	MsgError   = "error"
+)
+
+// Request is a line sent by a client.
+type Request struct {
+	Cmd     string        `json:"cmd"`
+	Project string        `json:"project,omitempty"` // with "sync": switch the default project first
+	Keys    []string      `json:"keys,omitempty"`    // with "changed"
+	Events  []cache.Event `json:"events,omitempty"`  // with "changed"
+}
+
+// Message is a line sent by the daemon.
+type Message struct {
+	Type   string        `json:"type"`
+	Status *Status       `json:"status,omitempty"`
+	Sync   *Report       `json:"sync,omitempty"`
+	Keys   []string      `json:"keys,omitempty"`
+	Events []cache.Event `json:"events,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// Status describes the running daemon.
+type Status struct {
+	PID         int       `json:"pid"`
+	Scopes      []string  `json:"scopes"`
+	Interval    int       `json:"interval"` // seconds between checks for due scopes
+	Syncing     bool      `json:"syncing"`
+	LastSync    time.Time `json:"last_sync"`
+	Last        *Report   `json:"last,omitempty"`
+	Subscribers int       `json:"subscribers"`
+}
+
+// Report is a cache.SyncResult in a form that survives JSON.
+type Report struct {
+	ItemsSynced  int           `json:"items_synced"`
+	WorklogsSent int           `json:"worklogs_sent"`
+	Events       []cache.Event `json:"events,omitempty"`
+	Pruned       int           `json:"pruned,omitempty"`
+	Rekeyed      int           `json:"rekeyed,omitempty"`
+	DurationMS   int64         `json:"duration_ms"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// NewReport converts a sync result.
+func NewReport(r cache.SyncResult) *Report {
+	rep := &Report{
+		ItemsSynced:  r.ItemsSynced,
+		WorklogsSent: r.WorklogsSent,
+		Events:       r.Events,
+		Pruned:       r.Pruned,
+		Rekeyed:      r.Rekeyed,
+		DurationMS:   r.Duration.Milliseconds(),
+	}
+	if r.Err != nil {
+		rep.Error = r.Err.Error()
+	}
+	return rep
+}
+
+// Result converts the report back into a sync result.
+func (r *Report) Result() cache.SyncResult {
+	res := cache.SyncResult{
+		ItemsSynced:  r.ItemsSynced,
+		WorklogsSent: r.WorklogsSent,
+		Events:       r.Events,
+		Pruned:       r.Pruned,
+		Rekeyed:      r.Rekeyed,
+		Duration:     time.Duration(r.DurationMS) * time.Millisecond,
+	}
+	if r.Error != "" {
+		res.Err = fmt.Errorf("%s", r.Error)
+	}
+	return res
+}
+
+// SocketPath returns the daemon's socket, next to the cache database.
+func SocketPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "shinkansen", "daemon.sock"), nil
+}
@@ -0,0 +1,269 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+	"github.com/temujinlabs/shinkansen/internal/notify"
+)
+
+// Server syncs the cache on a schedule and serves the socket API.
+type Server struct {
+	cfg      *config.Config
+	client   *jira.Client
+	store    *cache.Store
+	notifier *notify.Notifier
+
+	syncMu sync.Mutex // held for the duration of a sync
+
+	mu       sync.Mutex
+	syncing  bool
+	lastSync time.Time
+	last     *Report
+	subs     map[chan Message]struct{}
+}
+
+func NewServer(cfg *config.Config, client *jira.Client, store *cache.Store) *Server {
+	return &Server{
+		cfg:      cfg,
+		client:   client,
+		store:    store,
+		notifier: notify.New(cfg),
+		subs:     make(map[chan Message]struct{}),
+	}
+}
+
+// Run listens on the socket and syncs each scope on its interval until ctx
+// is done.
+// It refuses to start when another daemon answers on the socket.
+func (s *Server) Run(ctx context.Context) error {
+	path, err := SocketPath()
+	if err != nil {
+		return err
+	}
+	if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		c.Close()
+		return fmt.Errorf("a daemon is already running on %s", path)
+	}
+	os.Remove(path) // stale socket from a daemon that died
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer os.Remove(path)
+	os.Chmod(path, 0600)
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	go s.schedule(ctx)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			continue
+		}
+		go s.serve(ctx, conn)
+	}
+}
+
+func (s *Server) schedule(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.MinSyncInterval())
+	defer ticker.Stop()
+	for {
+		s.run(func() cache.SyncResult {
+			return cache.SyncDue(s.client, s.store, s.cfg.Scopes())
+		})
+		// Transitions aren't synced; cache them for the user's issues
+		// between syncs so pickers in every TUI open instantly
+		if n := cache.WarmTransitions(s.client, s.store); n > 0 {
+			log.Printf("cached transitions for %d issues", n)
+		}
+		// and fill in descriptions and comments for search
+		if n, err := cache.BackfillDetails(s.client, s.store); err != nil {
+			log.Printf("backfilling issue details: %v", err)
+		} else if n > 0 {
+			log.Printf("cached details of %d issues", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync syncs every scope now, after any sync in progress, and publishes
+// the result to subscribers.
+func (s *Server) Sync() *Report {
+	return s.run(func() cache.SyncResult {
+		return cache.Sync(s.client, s.store, s.cfg.Scopes())
+	})
+}
+
+// SetProject switches the default project until the daemon exits, adding a
+// scope for it as the TUI's project picker does. It waits for a sync in
+// progress.
+func (s *Server) SetProject(key string) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg.SwitchProject(key)
+}
+
+// run runs one sync and publishes its result.
+func (s *Server) run(sync func() cache.SyncResult) *Report {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	s.mu.Lock()
+	s.syncing = true
+	s.mu.Unlock()
+
+	result := sync()
+	rep := NewReport(result)
+
+	s.mu.Lock()
+	s.syncing = false
+	s.last = rep
+	if result.Err == nil {
+		s.lastSync = time.Now()
+	}
+	s.mu.Unlock()
+
+	if result.Err != nil {
+		log.Printf("sync failed: %v", result.Err)
+	} else {
+		log.Printf("synced %d issues in %dms, %d events", rep.ItemsSynced, rep.DurationMS, len(rep.Events))
+	}
+	if err := s.notifier.Notify(result.Events); err != nil {
+		log.Printf("notify: %v", err)
+	}
+	s.Publish(Message{Type: MsgSynced, Sync: rep})
+	return rep
+}
+
+// Changed tells subscribers that issues were updated in the cache outside a
+// sync, and shows notifications for their events.
+func (s *Server) Changed(keys []string, events []cache.Event) {
+	if err := s.notifier.Notify(events); err != nil {
+		log.Printf("notify: %v", err)
+	}
+	s.Publish(Message{Type: MsgChanged, Keys: keys, Events: events})
+}
+
+// Publish sends a message to every subscriber. Subscribers that fall
+// behind miss messages rather than stall the daemon.
+func (s *Server) Publish(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Status reports the daemon's state.
+func (s *Server) Status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Status{
+		PID:         os.Getpid(),
+		Scopes:      scopeNames(s.cfg.Scopes()),
+		Interval:    int(s.cfg.MinSyncInterval().Seconds()),
+		Syncing:     s.syncing,
+		LastSync:    s.lastSync,
+		Last:        s.last,
+		Subscribers: len(s.subs),
+	}
+}
+
+// serve answers one connection's requests.
+func (s *Server) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	var writeMu sync.Mutex
+	send := func(msg Message) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return json.NewEncoder(conn).Encode(msg)
+	}
+
+	var sub chan Message
+	defer func() {
+		if sub != nil {
+			s.mu.Lock()
+			delete(s.subs, sub)
+			s.mu.Unlock()
+			close(sub)
+		}
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			send(Message{Type: MsgError, Error: "bad request: " + err.Error()})
+			continue
+		}
+		switch req.Cmd {
+		case CmdStatus:
+			send(Message{Type: MsgStatus, Status: s.Status()})
+		case CmdSync:
+			if req.Project != "" {
+				s.SetProject(req.Project)
+			}
+			send(Message{Type: MsgSynced, Sync: s.Sync()})
+		case CmdChanged:
+			s.Changed(req.Keys, req.Events)
+			send(Message{Type: MsgChanged})
+		case CmdSubscribe:
+			if sub != nil {
+				continue
+			}
+			sub = make(chan Message, 16)
+			s.mu.Lock()
+			s.subs[sub] = struct{}{}
+			s.mu.Unlock()
+			go func(ch chan Message) {
+				for msg := range ch {
+					if send(msg) != nil {
+						conn.Close()
+						return
+					}
+				}
+			}(sub)
+		default:
+			send(Message{Type: MsgError, Error: fmt.Sprintf("unknown command %q", req.Cmd)})
+		}
+	}
+}
+
+func scopeNames(scopes []config.SyncScope) []string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = scope.Name
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// DownloadAttachment streams an attachment's content to w. The content
+// endpoint is addressed through the base URL so it works with OAuth too.
+func (c *Client) DownloadAttachment(id string, w io.Writer) error {
+	req, err := c.newRequest("GET", fmt.Sprintf("/rest/api/3/attachment/content/%s", url.PathEscape(id)), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download attachment: %w", err)
+	}
+	return nil
+}
+
+// UploadAttachment attaches a local file to an issue.
+func (c *Client) UploadAttachment(key, path string) ([]Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest("POST", fmt.Sprintf("/rest/api/3/issue/%s/attachments", url.PathEscape(key)), &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	// Jira rejects multipart uploads without this XSRF opt-out header
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var attachments []Attachment
+	if err := json.NewDecoder(resp.Body).Decode(&attachments); err != nil {
+		return nil, fmt.Errorf("parse attachments: %w", err)
+	}
+	return attachments, nil
+}
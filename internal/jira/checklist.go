@@ -0,0 +1,89 @@
+package jira
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// ChecklistItem is one checkbox item found in a description.
+type ChecklistItem struct {
+	Text string
+	Done bool
+}
+
+// checkboxRe matches text that starts with a Markdown or Unicode checkbox.
+var checkboxRe = regexp.MustCompile(`^\s*(?:[-*+]\s+)?(\[[ xX]\]|☐|☑|☒)\s*(.+)$`)
+
+// ChecklistItems returns the checklist items of an issue's description:
+// ADF task items, and list items or lines starting with "[ ]" / "[x]" or a
+// ☐/☑ box (as written by MarkdownDoc).
+func (i *Issue) ChecklistItems() []ChecklistItem {
+	raw := i.Fields.Description
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var node adfNode
+	if err := json.Unmarshal(raw, &node); err != nil || node.Type == "" {
+		// Plain text description
+		var s string
+		json.Unmarshal(raw, &s)
+		var items []ChecklistItem
+		for _, line := range strings.Split(s, "\n") {
+			if item, ok := checkboxItem(line); ok {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	var items []ChecklistItem
+	node.checklist(&items)
+	return items
+}
+
+// adfNode is the part of an ADF node needed to walk a document.
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text"`
+	Attrs   map[string]interface{} `json:"attrs"`
+	Content []adfNode              `json:"content"`
+}
+
+func (n *adfNode) checklist(items *[]ChecklistItem) {
+	switch n.Type {
+	case "taskItem":
+		if text := strings.TrimSpace(n.text()); text != "" {
+			*items = append(*items, ChecklistItem{Text: text, Done: n.Attrs["state"] == "DONE"})
+		}
+		return
+	case "paragraph":
+		if item, ok := checkboxItem(n.text()); ok {
+			*items = append(*items, item)
+		}
+		return
+	}
+	for i := range n.Content {
+		n.Content[i].checklist(items)
+	}
+}
+
+// text concatenates the text below a node.
+func (n *adfNode) text() string {
+	if n.Type == "text" {
+		return n.Text
+	}
+	var b strings.Builder
+	for i := range n.Content {
+		b.WriteString(n.Content[i].text())
+	}
+	return b.String()
+}
+
+func checkboxItem(line string) (ChecklistItem, bool) {
+	m := checkboxRe.FindStringSubmatch(line)
+	if m == nil {
+		return ChecklistItem{}, false
+	}
+	done := m[1] != "[ ]" && m[1] != "☐"
+	return ChecklistItem{Text: strings.TrimSpace(m[2]), Done: done}, true
+}
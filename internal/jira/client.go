@@ -2,10 +2,14 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/temujinlabs/shinkansen/internal/config"
@@ -17,6 +21,10 @@ type Client struct {
 	email      string
 	token      string
 	httpClient *http.Client
+
+	// Guards the OAuth refresh and the token and base URL it changes:
+	// scopes sync, searches page and transitions warm up concurrently
+	authMu sync.Mutex
 }
 
 func NewClient(baseURL, email, token string) *Client {
@@ -48,15 +56,93 @@ func NewClientFromConfig(cfg *config.Config) *Client {
 	}
 }
 
-func (c *Client) do(method, path string, body interface{}) ([]byte, error) {
-	// Auto-refresh OAuth token if expired
-	if c.cfg != nil && c.cfg.IsOAuth() && c.cfg.TokenExpired() {
+// AccountID returns the configured account ID of the current user, or ""
+// when the client was made without a config or before login stored it.
+func (c *Client) AccountID() string {
+	if c.cfg == nil {
+		return ""
+	}
+	return c.cfg.AccountID
+}
+
+// APIError is returned when Jira answers a request with an error status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// IsOffline reports whether err means Jira could not be reached at all: the
+// connection or DNS lookup failed, or the request timed out. Offline writes
+// can be retried later; a rejected request or a failed token refresh can't.
+func IsOffline(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// newRequest builds an authenticated request against the Jira base URL,
+// refreshing the OAuth token first if it has expired.
+func (c *Client) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	baseURL, auth, err := c.auth()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", auth)
+	return req, nil
+}
+
+// auth returns the base URL and Authorization header for a request. An
+// expired OAuth token is refreshed once, with other requests waiting for
+// it: refresh tokens rotate, so a second refresh with the same one would
+// end the session.
+func (c *Client) auth() (baseURL, header string, err error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.cfg == nil {
+		return c.baseURL, config.BasicAuthHeader(c.email, c.token), nil
+	}
+	if c.cfg.IsOAuth() && c.cfg.TokenExpired() {
 		if err := config.RefreshAccessToken(c.cfg); err != nil {
-			return nil, fmt.Errorf("token refresh: %w", err)
+			return "", "", fmt.Errorf("token refresh: %w", err)
 		}
 		c.baseURL = c.cfg.OAuthBaseURL()
 	}
+	return c.baseURL, c.cfg.AuthHeader(), nil
+}
 
+// send executes a request and turns error statuses into an APIError. The
+// caller must close the response body.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return resp, nil
+}
+
+func (c *Client) do(method, path string, body interface{}) ([]byte, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
@@ -66,23 +152,16 @@ func (c *Client) do(method, path string, body interface{}) ([]byte, error) {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := c.newRequest(method, path, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	if c.cfg != nil {
-		req.Header.Set("Authorization", c.cfg.AuthHeader())
-	} else {
-		req.Header.Set("Authorization", config.BasicAuthHeader(c.email, c.token))
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -90,11 +169,6 @@ func (c *Client) do(method, path string, body interface{}) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read response: %w", err)
 	}
-
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
-	}
-
 	return respBody, nil
 }
 
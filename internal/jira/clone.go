@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// cloneLinkType is Jira's built-in link type for clones ("clones" /
+// "is cloned by").
+const cloneLinkType = "Cloners"
+
+// CloneOptions controls what CloneIssue copies besides the basic fields.
+type CloneOptions struct {
+	Project     string // target project key, empty = same project
+	Links       bool   // recreate the source's issue links on the clone
+	Subtasks    bool   // clone the source's subtasks under the clone
+	SubtaskType string // the target project's sub-task type, needed to clone subtasks into another project
+}
+
+// CloneIssue copies an issue's summary, description, type, priority, labels
+// and components to a new issue and links it to the source with a "clones"
+// link. Components that don't exist in the target project are dropped. The
+// clone is returned even when copying links or subtasks fails part way.
+// Sub-tasks can only be cloned within their project, under the same parent.
+func (c *Client) CloneIssue(key string, opts CloneOptions) (*Issue, error) {
+	src, err := c.GetIssue(key)
+	if err != nil {
+		return nil, err
+	}
+	project := opts.Project
+	if project == "" {
+		project = src.Fields.Project.Key
+	}
+	if src.Fields.IssueType.Subtask && project != src.Fields.Project.Key {
+		return nil, fmt.Errorf("%s is a sub-task and can only be cloned within %s", key, src.Fields.Project.Key)
+	}
+	if opts.Subtasks && len(src.Fields.Subtasks) > 0 && project != src.Fields.Project.Key && opts.SubtaskType == "" {
+		return nil, fmt.Errorf("cloning subtasks into %s needs its sub-task type", project)
+	}
+
+	extra, err := c.cloneFields(src, project)
+	if err != nil {
+		return nil, err
+	}
+	if src.Fields.Parent != nil && project == src.Fields.Project.Key {
+		extra["parent"] = map[string]string{"key": src.Fields.Parent.Key}
+	}
+	clone, err := c.CreateIssueWithDetails(project, src.Fields.Summary, src.Fields.IssueType.Name, src.Fields.Priority.Name, "", extra)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.CreateIssueLink(cloneLinkType, clone.Key, src.Key); err != nil {
+		return clone, fmt.Errorf("link clone: %w", err)
+	}
+	if opts.Links {
+		for _, l := range src.Fields.IssueLinks {
+			if l.Type.Name == cloneLinkType {
+				continue
+			}
+			if l.OutwardIssue != nil {
+				err = c.CreateIssueLink(l.Type.Name, clone.Key, l.OutwardIssue.Key)
+			} else if l.InwardIssue != nil {
+				err = c.CreateIssueLink(l.Type.Name, l.InwardIssue.Key, clone.Key)
+			}
+			if err != nil {
+				return clone, fmt.Errorf("copy link: %w", err)
+			}
+		}
+	}
+	if opts.Subtasks && len(src.Fields.Subtasks) > 0 {
+		for _, st := range src.Fields.Subtasks {
+			sub, err := c.GetIssue(st.Key)
+			if err != nil {
+				return clone, fmt.Errorf("read subtask %s: %w", st.Key, err)
+			}
+			extra, err := c.cloneFields(sub, project)
+			if err != nil {
+				return clone, err
+			}
+			extra["parent"] = map[string]string{"key": clone.Key}
+			issueType := sub.Fields.IssueType.Name
+			if project != src.Fields.Project.Key {
+				issueType = opts.SubtaskType
+			}
+			if _, err := c.CreateIssueWithDetails(project, sub.Fields.Summary, issueType, sub.Fields.Priority.Name, "", extra); err != nil {
+				return clone, fmt.Errorf("clone subtask %s: %w", st.Key, err)
+			}
+		}
+	}
+	return clone, nil
+}
+
+// cloneFields returns the description, labels and components of src in
+// create format for an issue in project.
+func (c *Client) cloneFields(src *Issue, project string) (map[string]interface{}, error) {
+	extra := map[string]interface{}{}
+	if d := src.Fields.Description; len(d) > 0 && string(d) != "null" {
+		extra["description"] = json.RawMessage(d)
+	}
+	if len(src.Fields.Labels) > 0 {
+		extra["labels"] = src.Fields.Labels
+	}
+
+	names := src.Fields.ComponentNames()
+	if len(names) > 0 && project != src.Fields.Project.Key {
+		available, err := c.GetComponents(project)
+		if err != nil {
+			return nil, err
+		}
+		var kept []string
+		for _, n := range names {
+			for _, a := range available {
+				if a.Name == n {
+					kept = append(kept, n)
+					break
+				}
+			}
+		}
+		names = kept
+	}
+	if len(names) > 0 {
+		extra["components"] = NameRefs(names)
+	}
+	return extra, nil
+}
@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// FieldSchema describes the type of a field's value.
+type FieldSchema struct {
+	Type   string `json:"type"`             // string, number, option, user, date, datetime, array, ...
+	Items  string `json:"items,omitempty"`  // element type for arrays
+	System string `json:"system,omitempty"` // system field name, e.g. "priority"
+	Custom string `json:"custom,omitempty"` // custom field type key
+}
+
+// AllowedValue is one permitted value of an option-like field. Options use
+// Value; priorities, versions and components use Name.
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Label returns the value's display text.
+func (v AllowedValue) Label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.Value
+}
+
+// CreateField is a field on a project's create screen for one issue type.
+type CreateField struct {
+	FieldID         string         `json:"fieldId"`
+	Key             string         `json:"key"`
+	Name            string         `json:"name"`
+	Required        bool           `json:"required"`
+	HasDefaultValue bool           `json:"hasDefaultValue"`
+	Schema          FieldSchema    `json:"schema"`
+	AllowedValues   []AllowedValue `json:"allowedValues,omitempty"`
+}
+
+// createMetaPage is one page of a createmeta listing. Jira has used both
+// "values" and a named key for the items, so both are accepted.
+type createMetaPage struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	IssueTypes []json.RawMessage `json:"issueTypes"`
+	Fields     []json.RawMessage `json:"fields"`
+	Values     []json.RawMessage `json:"values"`
+}
+
+func (c *Client) createMetaPages(path string) ([]json.RawMessage, error) {
+	var all []json.RawMessage
+	for startAt := 0; ; {
+		data, err := c.do("GET", fmt.Sprintf("%s?startAt=%d&maxResults=50", path, startAt), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page createMetaPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("parse createmeta: %w", err)
+		}
+		items := append(append(page.IssueTypes, page.Fields...), page.Values...)
+		all = append(all, items...)
+		startAt += len(items)
+		if len(items) == 0 || startAt >= page.Total {
+			return all, nil
+		}
+	}
+}
+
+// GetCreateIssueTypes returns the issue types that can be created in a project.
+func (c *Client) GetCreateIssueTypes(projectKey string) ([]IssueType, error) {
+	items, err := c.createMetaPages(fmt.Sprintf("/rest/api/3/issue/createmeta/%s/issuetypes", url.PathEscape(projectKey)))
+	if err != nil {
+		return nil, err
+	}
+	types := make([]IssueType, 0, len(items))
+	for _, raw := range items {
+		var t IssueType
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, fmt.Errorf("parse issue type: %w", err)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// GetCreateFields returns the create screen fields for an issue type in a project.
+func (c *Client) GetCreateFields(projectKey, issueTypeID string) ([]CreateField, error) {
+	items, err := c.createMetaPages(fmt.Sprintf("/rest/api/3/issue/createmeta/%s/issuetypes/%s",
+		url.PathEscape(projectKey), url.PathEscape(issueTypeID)))
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]CreateField, 0, len(items))
+	for _, raw := range items {
+		var f CreateField
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, fmt.Errorf("parse field: %w", err)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// SearchUsers finds users by name or email.
+func (c *Client) SearchUsers(query string) ([]User, error) {
+	data, err := c.do("GET", "/rest/api/3/user/search?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	var users []User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("parse users: %w", err)
+	}
+	return users, nil
+}
@@ -0,0 +1,135 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field describes a Jira field as listed by GET /rest/api/3/field.
+type Field struct {
+	ID     string       `json:"id"`
+	Key    string       `json:"key"`
+	Name   string       `json:"name"`
+	Custom bool         `json:"custom"`
+	Schema *FieldSchema `json:"schema,omitempty"`
+}
+
+// GetFields returns all system and custom fields on the site.
+func (c *Client) GetFields() ([]Field, error) {
+	data, err := c.do("GET", "/rest/api/3/field", nil)
+	if err != nil {
+		return nil, err
+	}
+	var fields []Field
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("parse fields: %w", err)
+	}
+	return fields, nil
+}
+
+// customFieldIDs returns the custom field IDs mapped in the config, which are
+// requested alongside the standard fields.
+func (c *Client) customFieldIDs() []string {
+	if c.cfg == nil {
+		return nil
+	}
+	var ids []string
+	for _, name := range c.cfg.CustomFieldNames() {
+		ids = append(ids, c.cfg.CustomFields[name])
+	}
+	return ids
+}
+
+// issueFieldsAlias has IssueFields' fields without its JSON methods.
+type issueFieldsAlias IssueFields
+
+// UnmarshalJSON decodes the standard fields and keeps customfield_* values in Custom.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*issueFieldsAlias)(f)); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	f.Custom = nil
+	for k, v := range all {
+		if strings.HasPrefix(k, "customfield_") && string(v) != "null" {
+			if f.Custom == nil {
+				f.Custom = make(map[string]json.RawMessage)
+			}
+			f.Custom[k] = v
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes Custom back next to the standard fields, so cached
+// issues keep their custom field values.
+func (f IssueFields) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(issueFieldsAlias(f))
+	if err != nil || len(f.Custom) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range f.Custom {
+		all[k] = v
+	}
+	return json.Marshal(all)
+}
+
+// CustomText renders a custom field's value as plain text: options by their
+// value, users by name, arrays comma separated and rich text as plain text.
+func (f *IssueFields) CustomText(id string) string {
+	raw, ok := f.Custom[id]
+	if !ok {
+		return ""
+	}
+	return valueText(raw)
+}
+
+func valueText(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var items []json.RawMessage
+		json.Unmarshal(raw, &items)
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			if t := valueText(item); t != "" {
+				parts = append(parts, t)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			c := &Comment{Body: raw}
+			return c.BodyText()
+		}
+		for _, key := range []string{"value", "displayName", "name", "key"} {
+			if s, ok := v[key].(string); ok {
+				if child, ok := v["child"].(map[string]interface{}); ok && key == "value" {
+					if cs, ok := child["value"].(string); ok {
+						return s + " / " + cs // cascading select
+					}
+				}
+				return s
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jira's default time tracking settings: a day is 8 hours, a week is 5 days.
+const (
+	hoursPerDay = 8
+	daysPerWeek = 5
+)
+
+var durationUnits = map[string]time.Duration{
+	"w": daysPerWeek * hoursPerDay * time.Hour,
+	"d": hoursPerDay * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+}
+
+var durationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)([wdhm])`)
+
+// ParseDuration validates a Jira duration string such as "1w 2d 3h 30m" and
+// returns its length. Units must appear at most once and in w/d/h/m order,
+// matching what the worklog endpoint accepts.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return 0, fmt.Errorf("duration is empty")
+	}
+
+	var total time.Duration
+	order := "wdhm"
+	last := -1
+	for s != "" {
+		m := durationPart.FindStringSubmatch(s)
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration %q (use e.g. 1d 2h 30m)", s)
+		}
+		idx := strings.Index(order, m[2])
+		if idx <= last {
+			return 0, fmt.Errorf("unit %q repeated or out of order (use w d h m)", m[2])
+		}
+		last = idx
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", m[1])
+		}
+		total += time.Duration(n * float64(durationUnits[m[2]]))
+		s = strings.TrimSpace(s[len(m[0]):])
+	}
+	if total < time.Minute {
+		return 0, fmt.Errorf("duration must be at least 1m")
+	}
+	return total, nil
+}
+
+// FormatDuration renders a duration in Jira syntax with minute precision,
+// e.g. 90 minutes becomes "1h 30m". Weeks are not used so totals stay readable.
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes <= 0 {
+		return "0m"
+	}
+	var parts []string
+	if days := minutes / (hoursPerDay * 60); days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+		minutes -= days * hoursPerDay * 60
+	}
+	if hours := minutes / 60; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+		minutes -= hours * 60
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
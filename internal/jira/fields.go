@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetLabels returns all labels used on the Jira site. Labels are global, not
+// per project, so this is the closest thing to label metadata.
+func (c *Client) GetLabels() ([]string, error) {
+	var all []string
+	for startAt := 0; ; {
+		data, err := c.do("GET", fmt.Sprintf("/rest/api/3/label?startAt=%d&maxResults=1000", startAt), nil)
+		if err != nil {
+			return all, err
+		}
+		var page struct {
+			Values []string `json:"values"`
+			IsLast bool     `json:"isLast"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return all, fmt.Errorf("parse labels: %w", err)
+		}
+		all = append(all, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return all, nil
+		}
+		startAt += len(page.Values)
+	}
+}
+
+// GetComponents returns a project's components.
+func (c *Client) GetComponents(projectKey string) ([]Component, error) {
+	data, err := c.do("GET", fmt.Sprintf("/rest/api/3/project/%s/components", url.PathEscape(projectKey)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var components []Component
+	if err := json.Unmarshal(data, &components); err != nil {
+		return nil, fmt.Errorf("parse components: %w", err)
+	}
+	return components, nil
+}
+
+// GetVersions returns a project's versions, including released and archived ones.
+func (c *Client) GetVersions(projectKey string) ([]Version, error) {
+	data, err := c.do("GET", fmt.Sprintf("/rest/api/3/project/%s/versions", url.PathEscape(projectKey)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("parse versions: %w", err)
+	}
+	return versions, nil
+}
+
+// UpdateIssueFields sets fields on an issue, e.g. {"labels": []string{"ops"}}.
+func (c *Client) UpdateIssueFields(key string, fields map[string]interface{}) error {
+	body := map[string]interface{}{"fields": fields}
+	_, err := c.do("PUT", fmt.Sprintf("/rest/api/3/issue/%s", url.PathEscape(key)), body)
+	return err
+}
+
+// NameRefs turns names into [{"name": ...}] references, the form Jira
+// accepts for components and fix versions.
+func NameRefs(names []string) []map[string]string {
+	refs := make([]map[string]string, len(names))
+	for i, n := range names {
+		refs[i] = map[string]string{"name": n}
+	}
+	return refs
+}
@@ -4,10 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 func (c *Client) GetIssue(key string) (*Issue, error) {
-	path := fmt.Sprintf("/rest/api/3/issue/%s?fields=summary,description,status,assignee,reporter,priority,issuetype,project,created,updated,sprint,comment", url.PathEscape(key))
+	fields := "summary,description,status,assignee,reporter,priority,issuetype,project,created,updated,sprint,comment,attachment,watches,votes,labels,components,fixVersions,parent,subtasks,issuelinks"
+	if ids := c.customFieldIDs(); len(ids) > 0 {
+		fields += "," + strings.Join(ids, ",")
+	}
+	path := fmt.Sprintf("/rest/api/3/issue/%s?fields=%s&expand=changelog", url.PathEscape(key), fields)
 	data, err := c.do("GET", path, nil)
 	if err != nil {
 		return nil, err
@@ -19,6 +24,31 @@ func (c *Client) GetIssue(key string) (*Issue, error) {
 	return &issue, nil
 }
 
+// GetChangelog returns the full change history of an issue, oldest first.
+// GetIssue only embeds the most recent 100 entries, so the history tab pages
+// through the dedicated endpoint instead.
+func (c *Client) GetChangelog(key string) ([]ChangelogHistory, error) {
+	var all []ChangelogHistory
+	startAt := 0
+	for {
+		path := fmt.Sprintf("/rest/api/3/issue/%s/changelog?startAt=%d&maxResults=100", url.PathEscape(key), startAt)
+		data, err := c.do("GET", path, nil)
+		if err != nil {
+			return all, err
+		}
+		var page ChangelogPage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return all, fmt.Errorf("parse changelog: %w", err)
+		}
+		all = append(all, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	return all, nil
+}
+
 func (c *Client) CreateIssue(projectKey, summary, issueType string) (*Issue, error) {
 	req := CreateIssueRequest{
 		Fields: CreateIssueFields{
@@ -65,18 +95,7 @@ func (c *Client) TransitionIssue(key, transitionID string) error {
 func (c *Client) AddComment(key, text string) error {
 	// Jira Cloud v3 requires Atlassian Document Format (ADF) for comment bodies
 	body := map[string]interface{}{
-		"body": map[string]interface{}{
-			"version": 1,
-			"type":    "doc",
-			"content": []map[string]interface{}{
-				{
-					"type": "paragraph",
-					"content": []map[string]interface{}{
-						{"type": "text", "text": text},
-					},
-				},
-			},
-		},
+		"body": ADFDoc(text),
 	}
 	_, err := c.do("POST", fmt.Sprintf("/rest/api/3/issue/%s/comment", url.PathEscape(key)), body)
 	return err
@@ -88,16 +107,9 @@ func (c *Client) AssignIssue(key, accountID string) error {
 	return err
 }
 
-// LogWork adds a worklog entry to an issue.
-// timeSpent is a Jira duration string like "2h", "30m", "1d".
-func (c *Client) LogWork(key, timeSpent string) error {
-	body := map[string]string{"timeSpent": timeSpent}
-	_, err := c.do("POST", fmt.Sprintf("/rest/api/3/issue/%s/worklog", url.PathEscape(key)), body)
-	return err
-}
-
 // CreateIssueWithDetails creates an issue with full field support including priority and description.
-func (c *Client) CreateIssueWithDetails(projectKey, summary, issueType, priority, description string) (*Issue, error) {
+// The description is Markdown, see MarkdownDoc. extra holds any further fields (labels, components, ...) in Jira's create format.
+func (c *Client) CreateIssueWithDetails(projectKey, summary, issueType, priority, description string, extra map[string]interface{}) (*Issue, error) {
 	fields := map[string]interface{}{
 		"project":   map[string]string{"key": projectKey},
 		"summary":   summary,
@@ -108,18 +120,10 @@ func (c *Client) CreateIssueWithDetails(projectKey, summary, issueType, priority
 	}
 	if description != "" {
 		// Jira Cloud v3 requires ADF for description
-		fields["description"] = map[string]interface{}{
-			"version": 1,
-			"type":    "doc",
-			"content": []map[string]interface{}{
-				{
-					"type": "paragraph",
-					"content": []map[string]interface{}{
-						{"type": "text", "text": description},
-					},
-				},
-			},
-		}
+		fields["description"] = MarkdownDoc(description)
+	}
+	for k, v := range extra {
+		fields[k] = v
 	}
 
 	body := map[string]interface{}{"fields": fields}
@@ -142,3 +146,19 @@ func (c *Client) MoveToSprint(sprintID int, issueKeys ...string) error {
 	_, err := c.do("POST", fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID), body)
 	return err
 }
+
+// ADFDoc wraps plain text in a single-paragraph Atlassian Document Format doc.
+func ADFDoc(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"version": 1,
+		"type":    "doc",
+		"content": []map[string]interface{}{
+			{
+				"type": "paragraph",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": text},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,14 @@
+package jira
+
+// CreateIssueLink links two issues with a link type such as "Blocks" or
+// "Cloners". The API reads inwardKey with the type's outward description:
+// for Blocks, inwardKey blocks outwardKey.
+func (c *Client) CreateIssueLink(typeName, inwardKey, outwardKey string) error {
+	body := map[string]interface{}{
+		"type":         map[string]string{"name": typeName},
+		"inwardIssue":  map[string]string{"key": inwardKey},
+		"outwardIssue": map[string]string{"key": outwardKey},
+	}
+	_, err := c.do("POST", "/rest/api/3/issueLink", body)
+	return err
+}
@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DeleteIssue deletes an issue. Issues with subtasks can only be deleted
+// together with them.
+func (c *Client) DeleteIssue(key string, withSubtasks bool) error {
+	_, err := c.do("DELETE", fmt.Sprintf("/rest/api/3/issue/%s?deleteSubtasks=%t", url.PathEscape(key), withSubtasks), nil)
+	return err
+}
+
+// ArchiveIssues archives issues (Jira Premium and Enterprise only). Archived
+// issues disappear from search but can be restored by an admin.
+func (c *Client) ArchiveIssues(keys []string) error {
+	data, err := c.do("PUT", "/rest/api/3/issue/archive", map[string]interface{}{"issueIdsOrKeys": keys})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Errors map[string]struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	json.Unmarshal(data, &resp)
+	for _, e := range resp.Errors {
+		return fmt.Errorf("archive: %s", e.Message)
+	}
+	return nil
+}
+
+// IssueTypeStatuses lists the statuses an issue type uses in a project.
+type IssueTypeStatuses struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Subtask  bool     `json:"subtask"`
+	Statuses []Status `json:"statuses"`
+}
+
+// GetProjectStatuses returns the statuses of each issue type in a project.
+func (c *Client) GetProjectStatuses(projectKey string) ([]IssueTypeStatuses, error) {
+	data, err := c.do("GET", fmt.Sprintf("/rest/api/3/project/%s/statuses", url.PathEscape(projectKey)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var types []IssueTypeStatuses
+	if err := json.Unmarshal(data, &types); err != nil {
+		return nil, fmt.Errorf("parse project statuses: %w", err)
+	}
+	return types, nil
+}
+
+// MoveRequest moves issues to a project and issue type, mapping their
+// current status IDs to status IDs of the target workflow.
+type MoveRequest struct {
+	Keys        []string
+	Project     string
+	IssueTypeID string
+	StatusMap   map[string]string // source status ID -> target status ID
+}
+
+// MoveIssues moves issues to another project with the bulk move API and
+// waits for the move to finish. Fields that don't exist in the target are
+// filled with Jira's defaults.
+func (c *Client) MoveIssues(req MoveRequest) error {
+	// The target status takes the list of source statuses mapped onto it
+	targets := map[string][]map[string]interface{}{}
+	for from, to := range req.StatusMap {
+		if len(targets[to]) == 0 {
+			targets[to] = []map[string]interface{}{{"anyStatus": false, "statusIds": []string{}}}
+		}
+		targets[to][0]["statusIds"] = append(targets[to][0]["statusIds"].([]string), from)
+	}
+	mapping := map[string]interface{}{
+		"issueIdsOrKeys":              req.Keys,
+		"inferClassificationDefaults": true,
+		"inferFieldDefaults":          true,
+		"inferStatusDefaults":         len(targets) == 0,
+		"inferSubtaskTypeDefault":     true,
+	}
+	if len(targets) > 0 {
+		mapping["targetStatus"] = []map[string]interface{}{{"statuses": targets}}
+	}
+	body := map[string]interface{}{
+		"sendBulkNotification": true,
+		"targetToSourcesMapping": map[string]interface{}{
+			req.Project + "," + req.IssueTypeID: mapping,
+		},
+	}
+
+	data, err := c.do("POST", "/rest/api/3/bulk/issues/move", body)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		TaskID string `json:"taskId"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("parse move response: %w", err)
+	}
+	return c.waitTask(resp.TaskID, 2*time.Minute)
+}
+
+// waitTask polls a long-running Jira task until it finishes.
+func (c *Client) waitTask(taskID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		data, err := c.do("GET", "/rest/api/3/task/"+url.PathEscape(taskID), nil)
+		if err != nil {
+			return err
+		}
+		var task struct {
+			Status  string          `json:"status"`
+			Message string          `json:"message"`
+			Result  json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(data, &task); err != nil {
+			return fmt.Errorf("parse task: %w", err)
+		}
+		switch task.Status {
+		case "COMPLETE":
+			var result struct {
+				InvalidOrInaccessibleIssues []string            `json:"invalidOrInaccessibleIssues"`
+				FailedIssues                map[string][]string `json:"failedIssues"`
+			}
+			json.Unmarshal(task.Result, &result)
+			for key, errs := range result.FailedIssues {
+				return fmt.Errorf("%s: %v", key, errs)
+			}
+			if len(result.InvalidOrInaccessibleIssues) > 0 {
+				return fmt.Errorf("not moved: %v", result.InvalidOrInaccessibleIssues)
+			}
+			return nil
+		case "FAILED", "CANCELLED", "DEAD":
+			return fmt.Errorf("move %s: %s", task.Status, task.Message)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("move still running in Jira (task %s)", taskID)
+		}
+		time.Sleep(time.Second)
+	}
+}
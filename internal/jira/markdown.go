@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe  = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	taskRe     = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	inlineRe   = regexp.MustCompile("\\*\\*([^*]+)\\*\\*|`([^`]+)`")
+	fenceStart = "```"
+)
+
+// MarkdownDoc converts a small Markdown subset to an Atlassian Document
+// Format doc: headings, bullet and numbered lists, task items ("- [ ] x",
+// shown as ☐/☑ bullets), fenced code blocks, **bold** and `code`. Lines of
+// a paragraph are joined with hard breaks; anything else stays plain text.
+func MarkdownDoc(text string) map[string]interface{} {
+	var content []map[string]interface{}
+	var para []string
+	var list map[string]interface{}
+
+	flushPara := func() {
+		if len(para) == 0 {
+			return
+		}
+		var nodes []map[string]interface{}
+		for i, line := range para {
+			if i > 0 {
+				nodes = append(nodes, map[string]interface{}{"type": "hardBreak"})
+			}
+			nodes = append(nodes, inlineNodes(line)...)
+		}
+		content = append(content, map[string]interface{}{"type": "paragraph", "content": nodes})
+		para = nil
+	}
+	flushList := func() {
+		if list != nil {
+			content = append(content, list)
+			list = nil
+		}
+	}
+	addItem := func(listType, item string) {
+		flushPara()
+		if list != nil && list["type"] != listType {
+			flushList()
+		}
+		if list == nil {
+			list = map[string]interface{}{"type": listType, "content": []map[string]interface{}{}}
+		}
+		if m := taskRe.FindStringSubmatch(item); m != nil {
+			box := "☐ "
+			if m[1] != " " {
+				box = "☑ "
+			}
+			item = box + m[2]
+		}
+		list["content"] = append(list["content"].([]map[string]interface{}), map[string]interface{}{
+			"type": "listItem",
+			"content": []map[string]interface{}{
+				{"type": "paragraph", "content": inlineNodes(item)},
+			},
+		})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, fenceStart):
+			flushPara()
+			flushList()
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, fenceStart))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fenceStart); i++ {
+				code = append(code, lines[i])
+			}
+			block := map[string]interface{}{"type": "codeBlock"}
+			if lang != "" {
+				block["attrs"] = map[string]string{"language": lang}
+			}
+			if len(code) > 0 {
+				block["content"] = []map[string]interface{}{{"type": "text", "text": strings.Join(code, "\n")}}
+			}
+			content = append(content, block)
+		case trimmed == "":
+			flushPara()
+			flushList()
+		case headingRe.MatchString(trimmed):
+			flushPara()
+			flushList()
+			m := headingRe.FindStringSubmatch(trimmed)
+			content = append(content, map[string]interface{}{
+				"type":    "heading",
+				"attrs":   map[string]int{"level": len(m[1])},
+				"content": inlineNodes(m[2]),
+			})
+		case bulletRe.MatchString(line):
+			addItem("bulletList", bulletRe.FindStringSubmatch(line)[1])
+		case orderedRe.MatchString(line):
+			addItem("orderedList", orderedRe.FindStringSubmatch(line)[1])
+		default:
+			flushList()
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	flushList()
+
+	if len(content) == 0 {
+		content = []map[string]interface{}{{"type": "paragraph", "content": []map[string]interface{}{}}}
+	}
+	return map[string]interface{}{
+		"version": 1,
+		"type":    "doc",
+		"content": content,
+	}
+}
+
+// inlineNodes splits a line into text nodes, marking **bold** and `code`.
+func inlineNodes(line string) []map[string]interface{} {
+	var nodes []map[string]interface{}
+	text := func(s string, mark string) {
+		if s == "" {
+			return
+		}
+		node := map[string]interface{}{"type": "text", "text": s}
+		if mark != "" {
+			node["marks"] = []map[string]string{{"type": mark}}
+		}
+		nodes = append(nodes, node)
+	}
+
+	last := 0
+	for _, m := range inlineRe.FindAllStringSubmatchIndex(line, -1) {
+		text(line[last:m[0]], "")
+		if m[2] >= 0 {
+			text(line[m[2]:m[3]], "strong")
+		} else {
+			text(line[m[4]:m[5]], "code")
+		}
+		last = m[1]
+	}
+	text(line[last:], "")
+	return nodes
+}
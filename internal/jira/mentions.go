@@ -0,0 +1,28 @@
+package jira
+
+import "encoding/json"
+
+// Mentions reports whether an ADF document mentions the user with the
+// given account ID.
+func Mentions(body json.RawMessage, accountID string) bool {
+	if len(body) == 0 || accountID == "" {
+		return false
+	}
+	var node adfNode
+	if err := json.Unmarshal(body, &node); err != nil {
+		return false
+	}
+	return node.mentions(accountID)
+}
+
+func (n *adfNode) mentions(accountID string) bool {
+	if n.Type == "mention" && n.Attrs["id"] == accountID {
+		return true
+	}
+	for i := range n.Content {
+		if n.Content[i].mentions(accountID) {
+			return true
+		}
+	}
+	return false
+}
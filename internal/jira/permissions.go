@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Permission keys checked before offering actions.
+const (
+	PermAssignIssues     = "ASSIGN_ISSUES"
+	PermAssignable       = "ASSIGNABLE_USER" // can be the assignee
+	PermTransitionIssues = "TRANSITION_ISSUES"
+	PermEditIssues       = "EDIT_ISSUES"
+	PermAddComments      = "ADD_COMMENTS"
+	PermWorkOnIssues     = "WORK_ON_ISSUES" // log work
+	PermCreateIssues     = "CREATE_ISSUES"
+	PermCreateAttachment = "CREATE_ATTACHMENTS"
+	PermDeleteIssues     = "DELETE_ISSUES"
+	PermMoveIssues       = "MOVE_ISSUES"
+	PermAdminister       = "ADMINISTER" // global; needed to archive issues
+)
+
+// ActionPermissions are all the permissions the TUI checks, fetched together.
+var ActionPermissions = []string{
+	PermAssignIssues, PermAssignable, PermTransitionIssues, PermEditIssues,
+	PermAddComments, PermWorkOnIssues, PermCreateIssues, PermCreateAttachment,
+	PermDeleteIssues, PermMoveIssues, PermAdminister,
+}
+
+// MyPermissions reports which of the given permissions the current user has
+// in a project.
+func (c *Client) MyPermissions(projectKey string, keys ...string) (map[string]bool, error) {
+	path := fmt.Sprintf("/rest/api/3/mypermissions?projectKey=%s&permissions=%s",
+		url.QueryEscape(projectKey), url.QueryEscape(strings.Join(keys, ",")))
+	data, err := c.do("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Permissions map[string]struct {
+			HavePermission bool `json:"havePermission"`
+		} `json:"permissions"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse permissions: %w", err)
+	}
+	perms := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		perms[k] = resp.Permissions[k].HavePermission
+	}
+	return perms, nil
+}
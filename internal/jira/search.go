@@ -3,15 +3,60 @@ package jira
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
+// FieldSet chooses which issue fields a search returns.
+type FieldSet int
+
+const (
+	// FieldsFull is everything the cache keeps, descriptions and comments
+	// included.
+	FieldsFull FieldSet = iota
+	// FieldsList is what lists, boards and search show. Descriptions,
+	// comments and attachments are left out and loaded when the issue is
+	// opened.
+	FieldsList
+)
+
+var listFields = []string{"summary", "status", "assignee", "priority", "issuetype", "project", "updated", "created", "sprint", "reporter", "watches", "votes", "labels", "components", "fixVersions", "parent", "subtasks"}
+
+var detailFields = []string{"description", "comment", "attachment"}
+
+// Page sizes. Jira may return fewer issues per page when many fields are
+// requested; the page token takes care of the rest.
+const (
+	fullPageSize = 100
+	listPageSize = 1000
+)
+
+// fieldNames returns the fields to request for a field set, mapped custom
+// fields included.
+func (c *Client) fieldNames(set FieldSet) []string {
+	fields := append([]string(nil), listFields...)
+	if set == FieldsFull {
+		fields = append(fields, detailFields...)
+	}
+	return append(fields, c.customFieldIDs()...)
+}
+
+// HasDetails reports whether an issue was fetched with its description and
+// comments, rather than with FieldsList.
+func (i *Issue) HasDetails() bool {
+	return i.Fields.Comment != nil
+}
+
 // Search calls POST /rest/api/3/search/jql (the new endpoint).
 // Pagination uses nextPageToken, not startAt.
 func (c *Client) Search(jql string, maxResults int, nextPageToken string) (*SearchResult, error) {
+	return c.searchPage(jql, c.fieldNames(FieldsFull), maxResults, nextPageToken)
+}
+
+func (c *Client) searchPage(jql string, fields []string, maxResults int, nextPageToken string) (*SearchResult, error) {
 	body := map[string]interface{}{
 		"jql":        jql,
 		"maxResults": maxResults,
-		"fields":     []string{"summary", "status", "assignee", "priority", "issuetype", "project", "updated", "sprint", "comment", "description", "reporter", "created"},
+		"fields":     fields,
 	}
 	if nextPageToken != "" {
 		body["nextPageToken"] = nextPageToken
@@ -27,26 +72,121 @@ func (c *Client) Search(jql string, maxResults int, nextPageToken string) (*Sear
 	return &result, nil
 }
 
-// SearchAll pages through all results for a JQL query using token-based pagination.
-func (c *Client) SearchAll(jql string) ([]Issue, error) {
+// SearchKeys returns just the IDs and keys of every issue matching a JQL
+// query, in large pages, for checking which cached issues still exist.
+func (c *Client) SearchKeys(jql string) ([]Issue, error) {
 	var all []Issue
 	nextToken := ""
-
 	for {
-		result, err := c.Search(jql, 50, nextToken)
+		result, err := c.searchPage(jql, []string{"key"}, 1000, nextToken)
 		if err != nil {
-			return all, err
+			return nil, err
 		}
 		all = append(all, result.Issues...)
 		if result.IsLast || result.NextPageToken == nil || *result.NextPageToken == "" {
-			break
+			return all, nil
 		}
 		nextToken = *result.NextPageToken
 	}
-	return all, nil
+}
+
+// MatchJQL reports which of several JQL queries include an issue, asking
+// Jira once (POST /rest/api/3/jql/match) rather than searching per query.
+func (c *Client) MatchJQL(issueID string, jqls []string) ([]bool, error) {
+	id, err := strconv.ParseInt(issueID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("issue ID %q: %w", issueID, err)
+	}
+	data, err := c.do("POST", "/rest/api/3/jql/match", map[string]interface{}{
+		"issueIds": []int64{id},
+		"jqls":     jqls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Matches []struct {
+			MatchedIssues []int64  `json:"matchedIssues"`
+			Errors        []string `json:"errors"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse jql match: %w", err)
+	}
+	if len(result.Matches) != len(jqls) {
+		return nil, fmt.Errorf("jql match: %d results for %d queries", len(result.Matches), len(jqls))
+	}
+	matched := make([]bool, len(jqls))
+	for i, m := range result.Matches {
+		if len(m.Errors) > 0 {
+			return nil, fmt.Errorf("jql %q: %s", jqls[i], m.Errors[0])
+		}
+		matched[i] = len(m.MatchedIssues) > 0
+	}
+	return matched, nil
+}
+
+// SearchEach pages through all results for a JQL query and hands each
+// page to fn as it arrives. Pages follow each other's tokens, so they are
+// fetched in order, but the next one is requested while fn handles the
+// current one. An error from fn stops the search and is returned.
+func (c *Client) SearchEach(jql string, set FieldSet, fn func([]Issue) error) error {
+	fields := c.fieldNames(set)
+	size := listPageSize
+	if set == FieldsFull {
+		size = fullPageSize
+	}
+
+	type page struct {
+		issues []Issue
+		err    error
+	}
+	pages := make(chan page, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(pages)
+		nextToken := ""
+		for {
+			result, err := c.searchPage(jql, fields, size, nextToken)
+			if err != nil {
+				result = &SearchResult{}
+			}
+			select {
+			case pages <- page{issues: result.Issues, err: err}:
+			case <-done:
+				return
+			}
+			if err != nil || result.IsLast || result.NextPageToken == nil || *result.NextPageToken == "" {
+				return
+			}
+			nextToken = *result.NextPageToken
+		}
+	}()
+
+	for p := range pages {
+		if p.err != nil {
+			return p.err
+		}
+		if err := fn(p.issues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchAll returns all results for a JQL query.
+func (c *Client) SearchAll(jql string, set FieldSet) ([]Issue, error) {
+	var all []Issue
+	err := c.SearchEach(jql, set, func(issues []Issue) error {
+		all = append(all, issues...)
+		return nil
+	})
+	return all, err
 }
 
 // MyIssues returns issues assigned to the current user.
 func (c *Client) MyIssues() ([]Issue, error) {
-	return c.SearchAll("assignee = currentUser() AND resolution = Unresolved ORDER BY priority ASC, updated DESC")
+	return c.SearchAll("assignee = currentUser() AND resolution = Unresolved ORDER BY priority ASC, updated DESC", FieldsFull)
 }
@@ -30,3 +30,25 @@ func (c *Client) GetSprintIssues(sprintID int) ([]Issue, error) {
 	}
 	return resp.Issues, nil
 }
+
+// GetBoardFilterID returns the ID of the saved filter that selects a
+// board's issues.
+func (c *Client) GetBoardFilterID(boardID int) (string, error) {
+	path := fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID)
+	data, err := c.do("GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Filter struct {
+			ID string `json:"id"`
+		} `json:"filter"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("parse board configuration: %w", err)
+	}
+	if resp.Filter.ID == "" {
+		return "", fmt.Errorf("board %d has no filter", boardID)
+	}
+	return resp.Filter.ID, nil
+}
@@ -0,0 +1,125 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimesheetRow is the time one user logged on one issue, per weekday.
+type TimesheetRow struct {
+	IssueKey string
+	Summary  string
+	Days     [7]time.Duration // Monday first
+}
+
+// Total returns the time logged on the row's issue during the week.
+func (r *TimesheetRow) Total() time.Duration {
+	var t time.Duration
+	for _, d := range r.Days {
+		t += d
+	}
+	return t
+}
+
+// Timesheet aggregates a user's worklogs for one week, by issue and day.
+type Timesheet struct {
+	WeekStart time.Time // Monday 00:00, local time
+	Rows      []TimesheetRow
+}
+
+// DayTotal returns the time logged on a weekday (0 = Monday) across issues.
+func (ts *Timesheet) DayTotal(day int) time.Duration {
+	var t time.Duration
+	for _, r := range ts.Rows {
+		t += r.Days[day]
+	}
+	return t
+}
+
+// Total returns the time logged during the whole week.
+func (ts *Timesheet) Total() time.Duration {
+	var t time.Duration
+	for _, r := range ts.Rows {
+		t += r.Total()
+	}
+	return t
+}
+
+// Day returns the date of a weekday (0 = Monday) in the timesheet's week.
+func (ts *Timesheet) Day(day int) time.Time {
+	return ts.WeekStart.AddDate(0, 0, day)
+}
+
+// AddRow adds an issue without logged time so entries can be added to it.
+func (ts *Timesheet) AddRow(issueKey, summary string) {
+	for _, r := range ts.Rows {
+		if r.IssueKey == issueKey {
+			return
+		}
+	}
+	ts.Rows = append(ts.Rows, TimesheetRow{IssueKey: issueKey, Summary: summary})
+}
+
+// WeekStart returns Monday 00:00 of the week containing t, in t's location.
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// FormatHours renders a duration as hours and minutes, e.g. "7:30", the way
+// timesheets usually show it (Jira's "1d" would hide overtime).
+func FormatHours(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// Timesheet collects the worklogs accountID recorded during the week starting
+// at weekStart. Jira has no endpoint for a user's worklogs, so this finds the
+// issues with JQL and then filters each issue's worklogs.
+func (c *Client) Timesheet(accountID string, weekStart time.Time) (*Timesheet, error) {
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	jql := fmt.Sprintf(`worklogAuthor = currentUser() AND worklogDate >= "%s" AND worklogDate < "%s" ORDER BY key ASC`,
+		weekStart.Format("2006-01-02"), weekEnd.Format("2006-01-02"))
+	issues, err := c.SearchAll(jql, FieldsList)
+	if err != nil {
+		return nil, fmt.Errorf("search worklogs: %w", err)
+	}
+
+	if accountID == "" {
+		me, err := c.GetMyself()
+		if err != nil {
+			return nil, err
+		}
+		accountID = me.AccountID
+	}
+
+	ts := &Timesheet{WeekStart: weekStart}
+	for _, issue := range issues {
+		worklogs, err := c.GetWorklogs(issue.Key)
+		if err != nil {
+			return nil, fmt.Errorf("worklogs for %s: %w", issue.Key, err)
+		}
+		row := TimesheetRow{IssueKey: issue.Key, Summary: issue.Fields.Summary}
+		for _, w := range worklogs {
+			started := w.StartedTime().In(weekStart.Location())
+			if w.Author.AccountID != accountID || started.Before(weekStart) || !started.Before(weekEnd) {
+				continue
+			}
+			// Walk calendar days rather than dividing hours, which breaks across DST
+			day := 0
+			for day < 6 && !started.Before(weekStart.AddDate(0, 0, day+1)) {
+				day++
+			}
+			row.Days[day] += time.Duration(w.TimeSpentSeconds) * time.Second
+		}
+		if row.Total() > 0 {
+			ts.Rows = append(ts.Rows, row)
+		}
+	}
+	sort.SliceStable(ts.Rows, func(i, j int) bool {
+		return ts.Rows[i].Total() > ts.Rows[j].Total()
+	})
+	return ts, nil
+}
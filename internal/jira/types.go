@@ -2,16 +2,22 @@ package jira
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
 // Jira Cloud REST API v3 response types
 
+// jiraTimeLayout is the timestamp format Jira uses in responses and expects
+// for fields like worklog "started".
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
 type User struct {
 	AccountID    string `json:"accountId"`
 	DisplayName  string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
 	Active       bool   `json:"active"`
+	TimeZone     string `json:"timeZone,omitempty"` // IANA name, only from /myself
 }
 
 type Project struct {
@@ -21,8 +27,10 @@ type Project struct {
 }
 
 type IssueType struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask,omitempty"`
+	HierarchyLevel int    `json:"hierarchyLevel,omitempty"` // 1 = epic, -1 = sub-task
 }
 
 type Priority struct {
@@ -31,8 +39,25 @@ type Priority struct {
 }
 
 type Status struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	StatusCategory *StatusCategory `json:"statusCategory,omitempty"`
+}
+
+// StatusCategory groups statuses; Key is "new", "indeterminate" or "done".
+type StatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+// IsDone reports whether the status is in the done category. Without a
+// category (older cache entries) it guesses from the name.
+func (s Status) IsDone() bool {
+	if s.StatusCategory != nil {
+		return s.StatusCategory.Key == "done"
+	}
+	name := strings.ToLower(s.Name)
+	return strings.Contains(name, "done") || strings.Contains(name, "closed") || strings.Contains(name, "resolved")
 }
 
 type Sprint struct {
@@ -90,29 +115,199 @@ type TimeTracking struct {
 	TimeSpentSeconds         int    `json:"timeSpentSeconds,omitempty"`
 }
 
+type Worklog struct {
+	ID               string          `json:"id"`
+	IssueID          string          `json:"issueId"`
+	Author           User            `json:"author"`
+	Comment          json.RawMessage `json:"comment,omitempty"`
+	Started          string          `json:"started"`
+	Updated          string          `json:"updated"`
+	TimeSpent        string          `json:"timeSpent"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+}
+
+// CommentText extracts plain text from the ADF worklog comment.
+func (w *Worklog) CommentText() string {
+	if len(w.Comment) == 0 || string(w.Comment) == "null" {
+		return ""
+	}
+	c := &Comment{Body: w.Comment}
+	return c.BodyText()
+}
+
+// StartedTime parses the time the work was started.
+func (w *Worklog) StartedTime() time.Time {
+	t, _ := time.Parse(jiraTimeLayout, w.Started)
+	return t
+}
+
+type WorklogsResponse struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Worklogs   []Worklog `json:"worklogs"`
+}
+
+type Attachment struct {
+	ID        string `json:"id"`
+	Filename  string `json:"filename"`
+	Author    User   `json:"author"`
+	Created   string `json:"created"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Content   string `json:"content"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// IsImage reports whether the attachment can be previewed as an image.
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.MimeType, "image/")
+}
+
+// Component is a project component.
+type Component struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Version is a project version, used for fix versions.
+type Version struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Released bool   `json:"released"`
+	Archived bool   `json:"archived"`
+}
+
+// ComponentNames returns the names of the issue's components.
+func (f *IssueFields) ComponentNames() []string {
+	names := make([]string, len(f.Components))
+	for i, c := range f.Components {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// FixVersionNames returns the names of the issue's fix versions.
+func (f *IssueFields) FixVersionNames() []string {
+	names := make([]string, len(f.FixVersions))
+	for i, v := range f.FixVersions {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// Watches is the watcher summary embedded in an issue's fields.
+type Watches struct {
+	WatchCount int  `json:"watchCount"`
+	IsWatching bool `json:"isWatching"`
+}
+
+// Votes is the vote summary embedded in an issue's fields.
+type Votes struct {
+	Votes    int  `json:"votes"`
+	HasVoted bool `json:"hasVoted"`
+}
+
+// WatchersResponse is returned by GET /issue/{key}/watchers.
+type WatchersResponse struct {
+	WatchCount int    `json:"watchCount"`
+	IsWatching bool   `json:"isWatching"`
+	Watchers   []User `json:"watchers"`
+}
+
 type IssueFields struct {
-	Summary     string          `json:"summary"`
-	Description json.RawMessage `json:"description,omitempty"`
-	Status      Status    `json:"status"`
-	Assignee    *User     `json:"assignee,omitempty"`
-	Reporter    *User     `json:"reporter,omitempty"`
-	Priority    Priority  `json:"priority"`
-	IssueType   IssueType `json:"issuetype"`
-	Project     Project   `json:"project"`
-	Created     string    `json:"created"`
-	Updated     string    `json:"updated"`
-	Sprint      *Sprint   `json:"sprint,omitempty"`
-	TimeTracking *TimeTracking `json:"timetracking,omitempty"`
-	Comment     *struct {
+	Summary      string          `json:"summary"`
+	Description  json.RawMessage `json:"description,omitempty"`
+	Status       Status          `json:"status"`
+	Assignee     *User           `json:"assignee,omitempty"`
+	Reporter     *User           `json:"reporter,omitempty"`
+	Priority     Priority        `json:"priority"`
+	IssueType    IssueType       `json:"issuetype"`
+	Project      Project         `json:"project"`
+	Created      string          `json:"created"`
+	Updated      string          `json:"updated"`
+	Sprint       *Sprint         `json:"sprint,omitempty"`
+	TimeTracking *TimeTracking   `json:"timetracking,omitempty"`
+	Attachments  []Attachment    `json:"attachment,omitempty"`
+	Labels       []string        `json:"labels,omitempty"`
+	Components   []Component     `json:"components,omitempty"`
+	FixVersions  []Version       `json:"fixVersions,omitempty"`
+	Watches      *Watches        `json:"watches,omitempty"`
+	Votes        *Votes          `json:"votes,omitempty"`
+	Parent       *Issue          `json:"parent,omitempty"`
+	Subtasks     []Issue         `json:"subtasks,omitempty"`
+	IssueLinks   []IssueLink     `json:"issuelinks,omitempty"`
+	Comment      *struct {
 		Comments []Comment `json:"comments"`
 	} `json:"comment,omitempty"`
+
+	// Custom holds customfield_* values as returned by Jira, keyed by field ID
+	Custom map[string]json.RawMessage `json:"-"`
+}
+
+// IssueLinkType is a kind of link, e.g. Blocks: "blocks" / "is blocked by".
+type IssueLinkType struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward,omitempty"`
+	Outward string `json:"outward,omitempty"`
+}
+
+// IssueLink is a link as listed on an issue. Only the other end is set:
+// OutwardIssue when this issue is on the inward side, and vice versa.
+type IssueLink struct {
+	ID           string        `json:"id,omitempty"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *Issue        `json:"inwardIssue,omitempty"`
+	OutwardIssue *Issue        `json:"outwardIssue,omitempty"`
 }
 
 type Issue struct {
-	ID     string      `json:"id"`
-	Key    string      `json:"key"`
-	Self   string      `json:"self"`
-	Fields IssueFields `json:"fields"`
+	ID        string      `json:"id"`
+	Key       string      `json:"key"`
+	Self      string      `json:"self"`
+	Fields    IssueFields `json:"fields"`
+	Changelog *Changelog  `json:"changelog,omitempty"` // only present with expand=changelog
+}
+
+// ChangelogItem is a single field change within a history entry.
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// ChangelogHistory groups the field changes made by one author at one time.
+type ChangelogHistory struct {
+	ID      string          `json:"id"`
+	Author  User            `json:"author"`
+	Created string          `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+// CreatedTime parses the Jira timestamp of the history entry.
+func (h *ChangelogHistory) CreatedTime() time.Time {
+	t, _ := time.Parse(jiraTimeLayout, h.Created)
+	return t
+}
+
+type Changelog struct {
+	StartAt    int                `json:"startAt"`
+	MaxResults int                `json:"maxResults"`
+	Total      int                `json:"total"`
+	Histories  []ChangelogHistory `json:"histories"`
+}
+
+// ChangelogPage is the paginated response of GET /issue/{key}/changelog.
+type ChangelogPage struct {
+	StartAt    int                `json:"startAt"`
+	MaxResults int                `json:"maxResults"`
+	Total      int                `json:"total"`
+	IsLast     bool               `json:"isLast"`
+	Values     []ChangelogHistory `json:"values"`
 }
 
 // DescriptionText extracts plain text from the ADF description.
@@ -125,6 +320,16 @@ func (i *Issue) DescriptionText() string {
 	return c.BodyText()
 }
 
+// SubtaskProgress returns how many of the issue's subtasks are done.
+func (i *Issue) SubtaskProgress() (done, total int) {
+	for _, st := range i.Fields.Subtasks {
+		if st.Fields.Status.IsDone() {
+			done++
+		}
+	}
+	return done, len(i.Fields.Subtasks)
+}
+
 func (i *Issue) AssigneeName() string {
 	if i.Fields.Assignee != nil {
 		return i.Fields.Assignee.DisplayName
@@ -133,7 +338,7 @@ func (i *Issue) AssigneeName() string {
 }
 
 func (i *Issue) UpdatedTime() time.Time {
-	t, _ := time.Parse("2006-01-02T15:04:05.000-0700", i.Fields.Updated)
+	t, _ := time.Parse(jiraTimeLayout, i.Fields.Updated)
 	return t
 }
 
@@ -210,3 +415,13 @@ type TransitionRequest struct {
 type TypeIDRef struct {
 	ID string `json:"id"`
 }
+
+// WorklogInput describes a worklog entry to create or update.
+type WorklogInput struct {
+	TimeSpent      string    // Jira duration, e.g. "1h 30m"
+	Started        time.Time // zero means now
+	Comment        string
+	AdjustEstimate string // "auto" (default), "leave", "new" or "manual"
+	NewEstimate    string // remaining estimate when AdjustEstimate is "new"
+	ReduceBy       string // amount to reduce the estimate by when "manual"
+}
@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetWatchers returns the users watching an issue.
+func (c *Client) GetWatchers(key string) (*WatchersResponse, error) {
+	data, err := c.do("GET", fmt.Sprintf("/rest/api/3/issue/%s/watchers", url.PathEscape(key)), nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp WatchersResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parse watchers: %w", err)
+	}
+	return &resp, nil
+}
+
+// Watch adds a user as a watcher. The request body is the bare account ID.
+func (c *Client) Watch(key, accountID string) error {
+	_, err := c.do("POST", fmt.Sprintf("/rest/api/3/issue/%s/watchers", url.PathEscape(key)), accountID)
+	return err
+}
+
+// Unwatch removes a user from an issue's watchers.
+func (c *Client) Unwatch(key, accountID string) error {
+	_, err := c.do("DELETE", fmt.Sprintf("/rest/api/3/issue/%s/watchers?accountId=%s", url.PathEscape(key), url.QueryEscape(accountID)), nil)
+	return err
+}
+
+// Vote adds the current user's vote to an issue.
+func (c *Client) Vote(key string) error {
+	_, err := c.do("POST", fmt.Sprintf("/rest/api/3/issue/%s/votes", url.PathEscape(key)), nil)
+	return err
+}
+
+// Unvote removes the current user's vote from an issue.
+func (c *Client) Unvote(key string) error {
+	_, err := c.do("DELETE", fmt.Sprintf("/rest/api/3/issue/%s/votes", url.PathEscape(key)), nil)
+	return err
+}
@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// GetWorklogs returns all worklog entries of an issue, oldest first.
+func (c *Client) GetWorklogs(key string) ([]Worklog, error) {
+	var all []Worklog
+	startAt := 0
+	for {
+		path := fmt.Sprintf("/rest/api/3/issue/%s/worklog?startAt=%d&maxResults=1000", url.PathEscape(key), startAt)
+		data, err := c.do("GET", path, nil)
+		if err != nil {
+			return all, err
+		}
+		var resp WorklogsResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return all, fmt.Errorf("parse worklogs: %w", err)
+		}
+		all = append(all, resp.Worklogs...)
+		startAt += len(resp.Worklogs)
+		if len(resp.Worklogs) == 0 || startAt >= resp.Total {
+			break
+		}
+	}
+	return all, nil
+}
+
+// LogWork adds a worklog entry to an issue.
+// TimeSpent is a Jira duration string like "2h", "30m", "1d".
+func (c *Client) LogWork(key string, w WorklogInput) error {
+	path := fmt.Sprintf("/rest/api/3/issue/%s/worklog", url.PathEscape(key)) + estimateQuery(w)
+	_, err := c.do("POST", path, worklogBody(w))
+	return err
+}
+
+// UpdateWorklog replaces the time, start and comment of an existing entry.
+func (c *Client) UpdateWorklog(key, worklogID string, w WorklogInput) error {
+	path := fmt.Sprintf("/rest/api/3/issue/%s/worklog/%s", url.PathEscape(key), url.PathEscape(worklogID)) + estimateQuery(w)
+	_, err := c.do("PUT", path, worklogBody(w))
+	return err
+}
+
+// DeleteWorklog removes a worklog entry, letting Jira adjust the remaining
+// estimate automatically.
+func (c *Client) DeleteWorklog(key, worklogID string) error {
+	_, err := c.do("DELETE", fmt.Sprintf("/rest/api/3/issue/%s/worklog/%s", url.PathEscape(key), url.PathEscape(worklogID)), nil)
+	return err
+}
+
+func worklogBody(w WorklogInput) map[string]interface{} {
+	started := w.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+	body := map[string]interface{}{
+		"timeSpent": w.TimeSpent,
+		"started":   started.Format(jiraTimeLayout),
+	}
+	if w.Comment != "" {
+		body["comment"] = ADFDoc(w.Comment)
+	}
+	return body
+}
+
+// estimateQuery builds the adjustEstimate query string for worklog writes.
+func estimateQuery(w WorklogInput) string {
+	q := url.Values{}
+	switch w.AdjustEstimate {
+	case "leave":
+		q.Set("adjustEstimate", "leave")
+	case "new":
+		q.Set("adjustEstimate", "new")
+		q.Set("newEstimate", w.NewEstimate)
+	case "manual":
+		q.Set("adjustEstimate", "manual")
+		q.Set("reduceBy", w.ReduceBy)
+	default:
+		return ""
+	}
+	return "?" + q.Encode()
+}
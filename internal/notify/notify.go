@@ -0,0 +1,152 @@
+// Package notify shows desktop notifications for the events a sync records:
+// through a configured command, notify-send or the freedesktop
+// Notifications D-Bus service on Linux, or osascript on macOS.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/config"
+)
+
+// Notifier pops up notifications within the configured quiet hours and rate
+// limits. It is safe for concurrent use.
+type Notifier struct {
+	cfg    *config.NotifyConfig
+	browse func(issueKey string) string
+
+	mu   sync.Mutex
+	sent []time.Time // popups shown in the last hour
+}
+
+// New returns a notifier for the config, or nil when notifications are
+// off. A nil Notifier ignores events.
+func New(cfg *config.Config) *Notifier {
+	if cfg.Notify == nil || !cfg.Notify.Enabled {
+		return nil
+	}
+	return &Notifier{cfg: cfg.Notify, browse: cfg.BrowseURL}
+}
+
+// Notify pops up the wanted events. Over the per-sync or hourly limit, the
+// remaining events are summed up in a single popup.
+func (n *Notifier) Notify(events []cache.Event) error {
+	if n == nil || len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if n.cfg.InQuietHours(now) {
+		return nil
+	}
+	var wanted []cache.Event
+	for _, e := range events {
+		if n.cfg.Wants(e.Kind) {
+			wanted = append(wanted, e)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	recent := n.sent[:0]
+	for _, t := range n.sent {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	n.sent = recent
+	perSync, perHour := n.cfg.Limits()
+	budget := min(perSync, perHour-len(n.sent))
+	if budget <= 0 {
+		n.mu.Unlock()
+		return nil
+	}
+	single := wanted
+	if len(wanted) > budget {
+		single = wanted[:budget-1]
+	}
+	for i := 0; i < min(budget, len(wanted)); i++ {
+		n.sent = append(n.sent, now)
+	}
+	n.mu.Unlock()
+
+	for _, e := range single {
+		if err := n.show(title(e), e.Text, e.IssueKey, e.Kind); err != nil {
+			return err
+		}
+	}
+	if rest := len(wanted) - len(single); rest > 0 {
+		return n.show("Shinkansen", fmt.Sprintf("%d more changes to your issues — press N to see them", rest), "", "summary")
+	}
+	return nil
+}
+
+// Test shows a sample notification, to check the setup.
+func (n *Notifier) Test() error {
+	if n == nil {
+		return fmt.Errorf("notifications are off: set \"notify\": {\"enabled\": true} in config.json")
+	}
+	if err := n.cfg.Validate(); err != nil {
+		return err
+	}
+	return n.show("Shinkansen", "Notifications are working", "", "test")
+}
+
+func title(e cache.Event) string {
+	switch e.Kind {
+	case cache.EventMention:
+		return e.IssueKey + ": you were mentioned"
+	case cache.EventAssigned:
+		return e.IssueKey + ": assignment"
+	case cache.EventStatus:
+		return e.IssueKey + ": status changed"
+	case cache.EventComment:
+		return e.IssueKey + ": new comment"
+	case cache.EventPriority:
+		return e.IssueKey + ": priority raised"
+	}
+	return e.IssueKey
+}
+
+// show runs the configured command or the platform's notifier.
+func (n *Notifier) show(title, body, issueKey, kind string) error {
+	var cmd *exec.Cmd
+	switch {
+	case n.cfg.Command != "":
+		cmd = exec.Command("sh", "-c", n.cfg.Command)
+		url := ""
+		if issueKey != "" {
+			url = n.browse(issueKey)
+		}
+		cmd.Env = append(os.Environ(),
+			"SHINKANSEN_TITLE="+title,
+			"SHINKANSEN_BODY="+body,
+			"SHINKANSEN_ISSUE="+issueKey,
+			"SHINKANSEN_KIND="+kind,
+			"SHINKANSEN_URL="+url,
+		)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	default:
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			cmd = exec.Command("notify-send", "--app-name=shinkansen", title, body)
+		} else {
+			cmd = exec.Command("gdbus", "call", "--session",
+				"--dest", "org.freedesktop.Notifications",
+				"--object-path", "/org/freedesktop/Notifications",
+				"--method", "org.freedesktop.Notifications.Notify",
+				"shinkansen", "0", "", title, body, "[]", "{}", "-1")
+		}
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v %s", cmd.Args[0], err, out)
+	}
+	return nil
+}
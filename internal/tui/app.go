@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/temujinlabs/shinkansen/internal/cache"
 	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/daemon"
 	"github.com/temujinlabs/shinkansen/internal/jira"
+	"github.com/temujinlabs/shinkansen/internal/notify"
 )
 
 type view int
@@ -24,14 +27,34 @@ const (
 	viewCreate
 	viewFilter
 	viewProjectPicker
+	viewTimesheet
+	viewNotifications
 )
 
 // Messages
-type syncDoneMsg struct{ result cache.SyncResult }
+type syncDoneMsg struct {
+	result     cache.SyncResult
+	fromDaemon bool // run by the sync daemon, which also handles notifications
+}
 type tickMsg time.Time
+type notifyFailedMsg struct{ err error }
 type assignDoneMsg struct{ issueKey string }
-type logWorkDoneMsg struct{ issueKey string }
-type bulkMoveDoneMsg struct{ count int }
+type logWorkDoneMsg struct {
+	issueKey  string
+	deleted   bool
+	queued    bool // Jira was unreachable; the worklog waits for the next sync
+	fromTimer bool // logged the stopped timer, removed once logged or queued
+	err       error
+}
+type bulkMoveDoneMsg struct {
+	count int
+	err   error
+}
+type watchDoneMsg struct {
+	issueKey string
+	text     string
+	err      error
+}
 type statusMsg string
 type projectsFetchedMsg struct{ projects []jira.Project }
 type projectSwitchedMsg struct{ projectKey string }
@@ -50,11 +73,22 @@ type App struct {
 	detail        DetailView
 	search        SearchView
 	picker        TransitionPicker
+	fieldPicker   FieldPicker
 	create        CreateView
 	filter        FilterView
 	projectPicker ProjectPicker
+	timesheet     TimesheetView
+	notifications NotificationsView
+	deleteForm    DeleteForm
+	moveForm      MoveForm
 	showHelp      bool
 
+	// Permissions by project, loaded from Jira once per session
+	permissions   map[string]map[string]bool
+	permLoading   map[string]bool
+	pendingManage string      // "delete" or "move" waiting for permissions
+	pendingIssue  *jira.Issue // the issue that action was started on
+
 	// Selections for bulk operations
 	selections map[string]bool
 
@@ -65,6 +99,18 @@ type App struct {
 	lastSync   time.Time
 	syncing    bool
 	flashMsg   string // Temporary status message
+	unread     int    // unread notification events
+	scope      string // sync scope the list and board show, "" for all
+
+	timer        *cache.Timer // active work timer, refreshed every second
+	timerTicking bool
+	timerBeating bool // this TUI has been heartbeating the running timer
+
+	notifier *notify.Notifier // desktop notifications, nil when off
+
+	// Sync daemon, when one is running; nil means syncing in-process
+	daemon     *daemon.Client
+	daemonMsgs <-chan daemon.Message
 }
 
 func NewApp(client *jira.Client, store *cache.Store, cfg *config.Config) *App {
@@ -73,56 +119,123 @@ func NewApp(client *jira.Client, store *cache.Store, cfg *config.Config) *App {
 		store:         store,
 		cfg:           cfg,
 		currentView:   viewIssues,
-		issues:        NewIssueList(),
+		issues:        NewIssueList(cfg.ListColumns, cfg.CustomFields),
 		board:         NewBoardView(),
-		detail:        NewDetailView(),
+		detail:        NewDetailView(cfg.CustomFields),
 		search:        NewSearchView(),
 		create:        NewCreateView(),
 		filter:        NewFilterView(store),
 		projectPicker: NewProjectPicker(),
+		timesheet:     NewTimesheetView(),
 		selections:    make(map[string]bool),
+		permissions:   make(map[string]map[string]bool),
+		permLoading:   make(map[string]bool),
 		syncStatus:    "Loading...",
+		notifier:      notify.New(cfg),
 	}
 }
 
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(
-		a.doSync,
-		a.tickCmd(),
-	)
+	cmds := []tea.Cmd{a.connectDaemon(), a.syncCmd(), a.tickCmd(), a.preloadPermissions()}
+	if t, err := a.store.ActiveTimer(); err == nil && t != nil {
+		a.timer = t
+		cmds = append(cmds, a.startTimerTicks())
+	}
+	return tea.Batch(cmds...)
 }
 
+// tickCmd wakes up for the scope that syncs most often; each tick syncs the
+// scopes that are due.
 func (a *App) tickCmd() tea.Cmd {
-	interval := 60
-	if a.cfg.SyncInterval > 0 {
-		interval = a.cfg.SyncInterval
-	}
-	return tea.Tick(time.Duration(interval)*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(a.cfg.MinSyncInterval(), func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-func (a *App) doSync() tea.Msg {
-	result := cache.Sync(a.client, a.store, a.cfg.DefaultProject)
-	return syncDoneMsg{result: result}
+// notifyEvents pops up desktop notifications for events a sync recorded.
+func (a *App) notifyEvents(events []cache.Event) tea.Cmd {
+	if a.notifier == nil || len(events) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := a.notifier.Notify(events); err != nil {
+			return notifyFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// syncCmd syncs every scope. Actions run it too, so that with a daemon
+// running it stays the only process syncing. It must be called from Update:
+// the daemon connection is read there, as daemonGoneMsg closes it.
+func (a *App) syncCmd() tea.Cmd {
+	d, client, store, scopes := a.daemon, a.client, a.store, a.cfg.Scopes()
+	return func() tea.Msg {
+		// With a daemon, ask it to sync; the result arrives as a daemonMsg
+		if d != nil {
+			if _, err := d.Sync(); err == nil {
+				return nil
+			}
+		}
+		return syncDoneMsg{result: cache.Sync(client, store, scopes)}
+	}
+}
+
+// projectSyncCmd syncs after a project switch. A daemon is asked to switch
+// too, so that it syncs the project's scope for every TUI.
+func (a *App) projectSyncCmd(project string) tea.Cmd {
+	d, client, store, scopes := a.daemon, a.client, a.store, a.cfg.Scopes()
+	return func() tea.Msg {
+		if d != nil {
+			if _, err := d.SyncProject(project); err == nil {
+				return nil
+			}
+		}
+		return syncDoneMsg{result: cache.Sync(client, store, scopes)}
+	}
+}
+
+// warmTransitions caches transitions for the user's issues in the
+// background, so the move picker opens without a request.
+func (a *App) warmTransitions() tea.Msg {
+	cache.WarmTransitions(a.client, a.store)
+	return nil
+}
+
+// backfillDetails caches descriptions and comments of issues synced with
+// list fields only, so search finds text in issues not opened yet.
+func (a *App) backfillDetails() tea.Msg {
+	cache.BackfillDetails(a.client, a.store)
+	return nil
+}
+
+// scheduledSyncCmd syncs the scopes whose interval has passed.
+func (a *App) scheduledSyncCmd() tea.Cmd {
+	client, store, scopes := a.client, a.store, a.cfg.Scopes()
+	return func() tea.Msg {
+		return syncDoneMsg{result: cache.SyncDue(client, store, scopes)}
+	}
 }
 
 func (a *App) loadFromCache() {
-	issues, err := a.store.GetAllIssues()
+	issues, err := a.scopeIssues()
 	if err != nil {
 		return
 	}
-	a.issues.SetIssues(issues)
+	if a.issues.watching {
+		watched, _ := a.store.GetWatchedIssues()
+		a.issues.SetIssues(watched)
+	} else {
+		a.issues.SetIssues(issues)
+	}
 	a.board.SetIssues(issues)
+	a.refreshUnread()
 
 	// Refresh the detail view if it's showing an issue
 	if a.detail.issue != nil {
-		for i := range issues {
-			if issues[i].Key == a.detail.issue.Key {
-				a.detail.issue = &issues[i]
-				a.detail.commentSent = false
-				break
-			}
+		if issue, err := a.store.GetIssue(a.detail.issue.Key); err == nil {
+			a.detail.issue = issue
+			a.detail.commentSent = false
 		}
 	}
 }
@@ -132,7 +245,8 @@ func (a *App) isInputMode() bool {
 	if a.currentView == viewSearch {
 		return true
 	}
-	if a.currentView == viewDetail && (a.detail.commenting || a.detail.logging) {
+	if a.currentView == viewDetail && (a.detail.commenting || a.detail.logging || a.detail.confirmDelete || a.detail.uploading ||
+		a.detail.cloneForm.visible || a.detail.splitForm.visible || a.detail.addingSubtask) {
 		return true
 	}
 	if a.currentView == viewCreate {
@@ -141,6 +255,9 @@ func (a *App) isInputMode() bool {
 	if a.currentView == viewFilter {
 		return true
 	}
+	if a.currentView == viewTimesheet && a.timesheet.form.visible {
+		return true
+	}
 	return false
 }
 
@@ -199,10 +316,23 @@ func (a *App) doBulkTransition(transitionID string) tea.Cmd {
 		}
 	}
 	return func() tea.Msg {
+		var failed []string
+		moved := 0
 		for _, key := range keys {
-			a.client.TransitionIssue(key, transitionID)
+			err := a.client.TransitionIssue(key, transitionID)
+			if err == nil {
+				moved++
+				err = a.store.InvalidateTransitions(key)
+			}
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", key, err))
+			}
 		}
-		return bulkMoveDoneMsg{count: len(keys)}
+		msg := bulkMoveDoneMsg{count: moved}
+		if len(failed) > 0 {
+			msg.err = fmt.Errorf("%s", strings.Join(failed, "; "))
+		}
+		return msg
 	}
 }
 
@@ -220,37 +350,243 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.loadFromCache()
 		} else {
 			a.syncStatus = fmt.Sprintf("Synced %d issues in %dms", msg.result.ItemsSynced, msg.result.Duration.Milliseconds())
+			if msg.result.WorklogsSent > 0 {
+				a.syncStatus += fmt.Sprintf(", sent %d queued worklogs", msg.result.WorklogsSent)
+			}
+			// Rejected worklogs stay queued until retried or dropped
+			if n, err := a.store.RejectedWorklogs(); err == nil && n > 0 {
+				a.syncStatus += fmt.Sprintf(", %d queued worklogs rejected (see shinkansen timer queue)", n)
+			}
+			if n := msg.result.Pruned + msg.result.Rekeyed; n > 0 {
+				a.syncStatus += fmt.Sprintf(", removed %d and renamed %d stale issues", msg.result.Pruned, msg.result.Rekeyed)
+			}
+			if n := len(msg.result.Events); n > 0 {
+				a.syncStatus += fmt.Sprintf(", %d new notifications", n)
+			}
 			a.lastSync = time.Now()
 			a.loadFromCache()
 		}
+		if msg.fromDaemon {
+			a.syncStatus += " (daemon)"
+			return a, a.preloadPermissions()
+		}
+		return a, tea.Batch(a.preloadPermissions(), a.notifyEvents(msg.result.Events), a.warmTransitions, a.backfillDetails)
+
+	case notifyFailedMsg:
+		a.flashMsg = fmt.Sprintf("Desktop notification failed: %v", msg.err)
 		return a, nil
 
 	case transitionsMsg:
 		a.picker.Show(msg.issueKey, msg.transitions)
 		return a, nil
 
+	case historyLoadedMsg:
+		if a.detail.issue == nil || a.detail.issue.Key != msg.issueKey {
+			return a, nil
+		}
+		a.detail.historyLoading = false
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("History not refreshed, showing cache: %v", msg.err)
+			return a, nil
+		}
+		a.detail.history = msg.histories
+		return a, nil
+
+	case detailsLoadedMsg:
+		if a.detail.issue == nil || a.detail.issue.Key != msg.key {
+			return a, nil
+		}
+		a.detail.detailsLoading = false
+		if msg.issue != nil {
+			a.detail.issue = msg.issue
+		}
+		switch {
+		case msg.err != nil && msg.issue == nil:
+			a.flashMsg = fmt.Sprintf("Description and comments unavailable offline: %v", msg.err)
+		case msg.err != nil:
+			a.flashMsg = fmt.Sprintf("Loaded %s, but %v", msg.key, msg.err)
+		}
+		return a, nil
+
 	case assignDoneMsg:
 		a.flashMsg = fmt.Sprintf("Assigned %s to you", msg.issueKey)
 		a.syncing = true
-		return a, a.doSync
+		return a, a.syncCmd()
+
+	case permissionsMsg:
+		return a, a.handlePermissions(msg)
+
+	case issuesRemovedMsg:
+		a.handleIssuesRemoved(msg)
+		return a, nil
+
+	case moveTargetMsg:
+		a.moveForm.setTarget(msg)
+		return a, nil
+
+	case issueActionMsg:
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Failed: %v", msg.err)
+		} else {
+			a.flashMsg = msg.text
+		}
+		a.loadFromCache()
+		if a.currentView == viewDetail && a.detail.issue != nil && a.detail.issue.Key == msg.issueKey {
+			if issue, err := a.store.GetIssue(msg.issueKey); err == nil {
+				a.detail.issue = issue
+			}
+		}
+		return a, nil
 
 	case logWorkDoneMsg:
-		a.flashMsg = fmt.Sprintf("Time logged on %s", msg.issueKey)
+		a.detail.logSent = false
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Log work failed: %v", msg.err)
+			if msg.fromTimer {
+				a.flashMsg += fmt.Sprintf("; timer on %s kept (s to log it again)", msg.issueKey)
+			}
+			return a, nil
+		}
+		if msg.fromTimer {
+			if err := a.finishTimer(msg.issueKey); err != nil {
+				a.flashMsg = fmt.Sprintf("Worklog for %s saved, but the timer wasn't removed: %v", msg.issueKey, err)
+				return a, nil
+			}
+		}
+		if msg.queued {
+			a.flashMsg = fmt.Sprintf("Offline: worklog for %s queued until next sync", msg.issueKey)
+			return a, nil
+		}
+		if msg.deleted {
+			a.flashMsg = fmt.Sprintf("Worklog deleted on %s", msg.issueKey)
+		} else {
+			a.flashMsg = fmt.Sprintf("Time logged on %s", msg.issueKey)
+		}
+		if a.currentView == viewTimesheet {
+			return a, a.timesheet.load(a)
+		}
+		// Refresh the worklog tab if it is showing this issue
+		if a.detail.issue != nil && a.detail.issue.Key == msg.issueKey && a.detail.worklogKey == msg.issueKey {
+			return a, a.detail.loadWorklogs(a)
+		}
+		return a, nil
+
+	case previewReadyMsg:
+		return a, a.showPreview(msg)
+
+	case attachmentDoneMsg:
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Attachment failed: %v", msg.err)
+			return a, nil
+		}
+		if msg.text != "" {
+			a.flashMsg = msg.text
+		}
+		// Pick up a new upload from the refreshed cache
+		if a.detail.issue != nil && a.detail.issue.Key == msg.issueKey {
+			if issue, err := a.store.GetIssue(msg.issueKey); err == nil {
+				a.detail.issue = issue
+			}
+		}
+		return a, nil
+
+	case fieldOptionsMsg:
+		if a.fieldPicker.visible && a.fieldPicker.kind == msg.kind {
+			a.fieldPicker.setOptions(msg.options)
+			if msg.err != nil {
+				a.fieldPicker.errMsg = fmt.Sprintf("Offline, showing cached values: %v", msg.err)
+			}
+		}
+		return a, nil
+
+	case fieldsUpdatedMsg:
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Update failed: %v", msg.err)
+			return a, nil
+		}
+		a.flashMsg = fmt.Sprintf("Updated %s", msg.issueKey)
+		a.loadFromCache()
+		return a, nil
+
+	case watchDoneMsg:
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Failed: %v", msg.err)
+			return a, nil
+		}
+		a.flashMsg = msg.text
+		a.loadFromCache()
+		if a.detail.issue != nil && a.detail.issue.Key == msg.issueKey {
+			return a, a.detail.loadWatchers(a)
+		}
+		return a, nil
+
+	case watchersLoadedMsg:
+		if a.detail.issue != nil && a.detail.issue.Key == msg.issueKey && msg.err == nil {
+			a.detail.watchers = msg.watchers.Watchers
+		}
+		return a, nil
+
+	case timesheetLoadedMsg:
+		if !msg.weekStart.Equal(a.timesheet.weekStart) {
+			return a, nil // the user moved to another week meanwhile
+		}
+		a.timesheet.loading = false
+		if msg.err != nil {
+			a.timesheet.errMsg = fmt.Sprintf("Failed to load timesheet: %v", msg.err)
+			return a, nil
+		}
+		a.timesheet.sheet = msg.sheet
+		if a.timesheet.row >= len(msg.sheet.Rows) {
+			a.timesheet.row = max(0, len(msg.sheet.Rows)-1)
+		}
+		return a, nil
+
+	case worklogsLoadedMsg:
+		if a.detail.issue == nil || a.detail.issue.Key != msg.issueKey {
+			return a, nil
+		}
+		a.detail.worklogLoading = false
+		if msg.err != nil {
+			a.flashMsg = fmt.Sprintf("Failed to load worklogs: %v", msg.err)
+			return a, nil
+		}
+		a.detail.worklogs = msg.worklogs
+		if a.detail.worklogCursor >= len(msg.worklogs) {
+			a.detail.worklogCursor = max(0, len(msg.worklogs)-1)
+		}
 		return a, nil
 
 	case bulkMoveDoneMsg:
 		a.flashMsg = fmt.Sprintf("Moved %d issues", msg.count)
+		if msg.err != nil {
+			a.flashMsg += fmt.Sprintf(", failed: %v", msg.err)
+		}
 		a.clearSelections()
 		a.syncing = true
-		return a, a.doSync
+		return a, a.syncCmd()
 
 	case createDoneMsg:
 		a.flashMsg = fmt.Sprintf("Created %s", msg.issueKey)
 		a.syncing = true
-		return a, a.doSync
+		return a, a.syncCmd()
 
 	case createErrMsg:
 		a.flashMsg = fmt.Sprintf("Create failed: %v", msg.err)
+		if msg.created {
+			a.syncing = true
+			return a, a.syncCmd()
+		}
+		return a, nil
+
+	case createProjectsMsg:
+		a.create.setProjects(msg.projects)
+		return a, nil
+
+	case createTypesMsg:
+		return a, a.create.setTypes(msg, a)
+
+	case createFieldsMsg:
+		a.create.setFields(msg)
 		return a, nil
 
 	case projectsFetchedMsg:
@@ -258,11 +594,17 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case projectSwitchedMsg:
+		// The switch lasts for this session: the config file is left alone
+		scope, changed := a.cfg.SwitchProject(msg.projectKey)
 		a.flashMsg = fmt.Sprintf("Switched to project %s", msg.projectKey)
-		a.cfg.DefaultProject = msg.projectKey
-		config.Save(a.cfg)
+		a.scope = scope
+		a.issues.cursor, a.issues.offset = 0, 0
+		a.loadFromCache()
+		if !changed {
+			return a, nil
+		}
 		a.syncing = true
-		return a, a.doSync
+		return a, a.projectSyncCmd(msg.projectKey)
 
 	case filterAppliedMsg:
 		a.issues.SetIssues(msg.issues)
@@ -274,11 +616,36 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.flashMsg = string(msg)
 		return a, nil
 
+	case timerTickMsg:
+		return a, a.handleTimerTick(time.Time(msg))
+
+	case daemonMsg:
+		if msg.Type == daemon.MsgChanged {
+			// Issues updated by the webhook receiver
+			a.loadFromCache()
+			a.flashMsg = "Updated " + strings.Join(msg.Keys, ", ")
+			return a, a.waitDaemon()
+		}
+		if msg.Type != daemon.MsgSynced || msg.Sync == nil {
+			return a, a.waitDaemon()
+		}
+		m, cmd := a.Update(syncDoneMsg{result: msg.Sync.Result(), fromDaemon: true})
+		return m, tea.Batch(cmd, a.waitDaemon())
+
+	case daemonGoneMsg:
+		a.daemon.Close()
+		a.daemon, a.daemonMsgs = nil, nil
+		a.flashMsg = "Sync daemon stopped; syncing in-process"
+		return a, a.syncCmd()
+
 	case tickMsg:
+		if a.daemon != nil {
+			return a, a.tickCmd() // the daemon syncs on its own schedule
+		}
 		a.syncing = true
 		a.syncStatus = "Syncing..."
 		a.flashMsg = ""
-		return a, tea.Batch(a.doSync, a.tickCmd())
+		return a, tea.Batch(a.scheduledSyncCmd(), a.tickCmd())
 
 	case tea.KeyMsg:
 		// Transition picker captures all input when visible
@@ -288,6 +655,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, cmd
 		}
 
+		// Field picker captures all input when visible
+		if a.fieldPicker.visible {
+			var cmd tea.Cmd
+			a.fieldPicker, cmd = a.fieldPicker.Update(msg, a)
+			return a, cmd
+		}
+
 		// Project picker captures all input when visible
 		if a.projectPicker.visible {
 			var cmd tea.Cmd
@@ -295,6 +669,18 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, cmd
 		}
 
+		// Delete and move forms capture all input when visible
+		if a.deleteForm.visible {
+			var cmd tea.Cmd
+			a.deleteForm, cmd = a.deleteForm.Update(msg, a)
+			return a, cmd
+		}
+		if a.moveForm.visible {
+			var cmd tea.Cmd
+			a.moveForm, cmd = a.moveForm.Update(msg, a)
+			return a, cmd
+		}
+
 		// In input mode (search, commenting, logging, create, filter), only ctrl+c quits
 		if a.isInputMode() {
 			if msg.String() == "ctrl+c" {
@@ -310,6 +696,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.create, cmd = a.create.Update(msg, a)
 			case viewFilter:
 				a.filter, cmd = a.filter.Update(msg, a)
+			case viewTimesheet:
+				a.timesheet, cmd = a.timesheet.Update(msg, a)
 			}
 			return a, cmd
 		}
@@ -317,7 +705,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Global keys (only active when NOT in input mode)
 		switch msg.String() {
 		case "q", "ctrl+c":
-			if a.currentView == viewDetail {
+			if a.currentView == viewDetail || a.currentView == viewTimesheet || a.currentView == viewNotifications {
 				a.currentView = viewIssues
 				return a, nil
 			}
@@ -331,18 +719,37 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.showHelp = !a.showHelp
 			return a, nil
 
+		case "P":
+			a.togglePauseTimer()
+			return a, nil
+
+		case "N":
+			if a.currentView != viewNotifications {
+				a.currentView = viewNotifications
+				a.notifications.Show(a)
+				return a, nil
+			}
+
+		case "T":
+			if a.currentView != viewTimesheet {
+				a.currentView = viewTimesheet
+				return a, a.timesheet.Show(a)
+			}
+
 		case "r":
 			if a.currentView != viewDetail {
 				a.syncing = true
 				a.syncStatus = "Syncing..."
-				return a, a.doSync
+				return a, a.syncCmd()
 			}
 
 		case "n":
 			if a.currentView != viewDetail {
+				if reason := a.denial("create", a.cfg.DefaultProject); reason != "" {
+					a.flashMsg = reason + " (pick another project in the form)"
+				}
 				a.currentView = viewCreate
-				a.create.Show()
-				return a, nil
+				return a, a.create.Show(a)
 			}
 
 		case "f":
@@ -352,6 +759,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, nil
 			}
 
+		case "g":
+			if a.currentView == viewIssues || a.currentView == viewBoard {
+				a.nextScope()
+				return a, nil
+			}
+
 		case "p":
 			if a.currentView != viewDetail {
 				a.projectPicker.Show()
@@ -409,7 +822,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					issueKey = a.detail.issue.Key
 				}
 			}
-			if issueKey != "" {
+			if issueKey != "" && !a.blocked("assign", issueKey) {
 				key := issueKey
 				accountID := a.cfg.AccountID
 				a.flashMsg = fmt.Sprintf("Assigning %s...", key)
@@ -495,6 +908,21 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.create, cmd = a.create.Update(msg, a)
 	case viewFilter:
 		a.filter, cmd = a.filter.Update(msg, a)
+	case viewTimesheet:
+		a.timesheet, cmd = a.timesheet.Update(msg, a)
+	case viewNotifications:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			a.notifications, cmd = a.notifications.Update(keyMsg, a)
+		}
+	}
+
+	// Fetch the watcher list once for each issue opened in the detail view
+	if a.currentView == viewDetail && a.detail.issue != nil && a.detail.watchersKey != a.detail.issue.Key {
+		cmd = tea.Batch(cmd, a.detail.loadWatchers(a), a.loadPermissions(a.detail.issue.Fields.Project.Key))
+	}
+	// and its description and comments, when the sync left them out
+	if a.currentView == viewDetail && a.detail.issue != nil && a.detail.detailsKey != a.detail.issue.Key {
+		cmd = tea.Batch(cmd, a.detail.loadDetails(a))
 	}
 	return a, cmd
 }
@@ -509,32 +937,47 @@ func (a *App) View() string {
 		return a.picker.View(a.width, a.height)
 	}
 
+	// Field picker overlay
+	if a.fieldPicker.visible {
+		return a.fieldPicker.View(a.width, a.height)
+	}
+
 	// Project picker overlay
 	if a.projectPicker.visible {
 		return a.projectPicker.View(a.width, a.height)
 	}
 
+	// Delete and move overlays
+	if a.deleteForm.visible {
+		return a.deleteForm.View(a.width, a.height)
+	}
+	if a.moveForm.visible {
+		return a.moveForm.View(a.width, a.height)
+	}
+
 	if a.showHelp {
 		return a.renderHelp()
 	}
 
 	// Build header with hints and status
-	selCount := a.selectionCount()
-	var hints string
-	if selCount > 0 {
-		hints = helpKeyStyle.Render(fmt.Sprintf("[%d selected]", selCount)) + "  " +
-			helpDescStyle.Render("m:move all  space:toggle  esc:clear")
-	} else {
-		hints = helpDescStyle.Render("enter:open  n:new  f:filter  p:project  o:browser  a:assign  m:move  ?:help")
-	}
+	hints := a.headerHints()
 
 	status := a.syncStatus
 	if a.flashMsg != "" {
 		status = a.flashMsg
 	}
 
-	header := titleStyle.Render("SHINKANSEN") + "  " + hints + "  " +
-		statusBarStyle.Render(status)
+	header := titleStyle.Render("SHINKANSEN") + "  " + hints + "  "
+	if t := a.renderTimer(); t != "" {
+		header += t + "  "
+	}
+	if n := a.renderUnread(); n != "" {
+		header += n + "  "
+	}
+	if s := a.renderScope(); s != "" {
+		header += s + "  "
+	}
+	header += statusBarStyle.Render(status)
 
 	// Reserve space: 1 header + 1 footer + 1 margin = 3 lines
 	contentHeight := a.height - 3
@@ -542,13 +985,17 @@ func (a *App) View() string {
 	var content string
 	switch a.currentView {
 	case viewDetail:
-		content = a.detail.View(a.width, contentHeight)
+		content = a.detail.View(a.width, contentHeight, a.cfg.AccountID, a.detailHints())
 	case viewSearch:
 		content = a.search.View(a.width, contentHeight)
 	case viewCreate:
 		content = a.create.View(a.width, contentHeight)
 	case viewFilter:
 		content = a.filter.View(a.width, contentHeight)
+	case viewTimesheet:
+		content = a.timesheet.View(a.width, contentHeight, a.cfg.DailyTargetHours)
+	case viewNotifications:
+		content = a.notifications.View(a.width, contentHeight)
 	default:
 		// Side-by-side: issues | board
 		halfWidth := a.width/2 - 2
@@ -570,20 +1017,35 @@ func (a *App) renderHelp() string {
 		helpKeyStyle.Render("↑/↓      ")+" "+helpDescStyle.Render("Navigate up/down"),
 		helpKeyStyle.Render("←/→      ")+" "+helpDescStyle.Render("Switch between panels & columns"),
 		helpKeyStyle.Render("Enter    ")+" "+helpDescStyle.Render("Open issue detail"),
+		helpKeyStyle.Render("Tab      ")+" "+helpDescStyle.Render("Switch detail tab (overview, history, worklog, attachments, subtasks)"),
 		helpKeyStyle.Render("o        ")+" "+helpDescStyle.Render("Open issue in browser"),
 		helpKeyStyle.Render("a        ")+" "+helpDescStyle.Render("Assign issue to yourself"),
 		helpKeyStyle.Render("m        ")+" "+helpDescStyle.Render("Move issue (status transition)"),
 		helpKeyStyle.Render("c        ")+" "+helpDescStyle.Render("Add comment"),
-		helpKeyStyle.Render("t        ")+" "+helpDescStyle.Render("Log time (e.g. 2h, 30m)"),
+		helpKeyStyle.Render("t        ")+" "+helpDescStyle.Render("Log work (time, start, comment, estimate)"),
+		helpKeyStyle.Render("e / x    ")+" "+helpDescStyle.Render("Edit / delete own worklog (worklog tab)"),
+		helpKeyStyle.Render("d / u / i")+" "+helpDescStyle.Render("Download / upload / preview attachment (attachments tab)"),
+		helpKeyStyle.Render("w / v    ")+" "+helpDescStyle.Render("Watch / vote on issue (detail); w toggles Watching list"),
+		helpKeyStyle.Render("l / C / V")+" "+helpDescStyle.Render("Edit labels / components / fix versions (detail)"),
+		helpKeyStyle.Render("n / x    ")+" "+helpDescStyle.Render("Add subtask / toggle subtask done (subtasks tab)"),
+		helpKeyStyle.Render("X / M    ")+" "+helpDescStyle.Render("Delete or archive / move to another project (detail or selection)"),
+		helpKeyStyle.Render("Y / S    ")+" "+helpDescStyle.Render("Clone issue / split checklist into child issues (detail)"),
+		helpKeyStyle.Render("s        ")+" "+helpDescStyle.Render("Start/stop work timer on issue"),
+		helpKeyStyle.Render("P        ")+" "+helpDescStyle.Render("Pause/resume work timer"),
+		helpKeyStyle.Render("T        ")+" "+helpDescStyle.Render("Weekly timesheet"),
+		helpKeyStyle.Render("N        ")+" "+helpDescStyle.Render("Notifications: changes to your issues since you last looked"),
 		helpKeyStyle.Render("n        ")+" "+helpDescStyle.Render("Create new issue"),
 		helpKeyStyle.Render("f        ")+" "+helpDescStyle.Render("JQL filter (custom query)"),
 		helpKeyStyle.Render("p        ")+" "+helpDescStyle.Render("Switch project"),
+		helpKeyStyle.Render("g        ")+" "+helpDescStyle.Render("Cycle the sync scope the list and board show"),
 		helpKeyStyle.Render("Space    ")+" "+helpDescStyle.Render("Select/deselect issue (bulk ops)"),
-		helpKeyStyle.Render("/        ")+" "+helpDescStyle.Render("Fuzzy search"),
+		helpKeyStyle.Render("/        ")+" "+helpDescStyle.Render("Search summaries, descriptions and comments"),
 		helpKeyStyle.Render("r        ")+" "+helpDescStyle.Render("Refresh / sync from Jira"),
 		helpKeyStyle.Render("?        ")+" "+helpDescStyle.Render("Toggle this help"),
 		helpKeyStyle.Render("q        ")+" "+helpDescStyle.Render("Quit"),
 		"",
+		disabledHintStyle.Render("x:key")+" "+helpDescStyle.Render("Greyed-out keys need a Jira permission you don't have in that project"),
+		"",
 		helpDescStyle.Render("Press any key to close"),
 	)
 	return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, help)
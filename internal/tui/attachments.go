@@ -0,0 +1,364 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// attachmentDoneMsg is sent after an attachment download, open or upload.
+type attachmentDoneMsg struct {
+	issueKey string
+	text     string
+	err      error
+}
+
+// previewReadyMsg is sent when an image to preview has been downloaded.
+type previewReadyMsg struct {
+	issueKey string
+	path     string // temp file, removed after the preview
+	protocol string
+	err      error
+}
+
+// selectedAttachment returns the attachment under the cursor.
+func (dv *DetailView) selectedAttachment() *jira.Attachment {
+	if dv.issue == nil || dv.attachCursor < 0 || dv.attachCursor >= len(dv.issue.Fields.Attachments) {
+		return nil
+	}
+	return &dv.issue.Fields.Attachments[dv.attachCursor]
+}
+
+// downloadAttachment saves an attachment into dir and returns its path.
+// Existing files are not overwritten; a numeric suffix is added instead.
+func downloadAttachment(client *jira.Client, att jira.Attachment, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := filepath.Base(att.Filename)
+	ext := filepath.Ext(name)
+	path := filepath.Join(dir, name)
+	for n := 1; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := client.DownloadAttachment(att.ID, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// attachmentAction runs download ("d") or open ("enter") for an attachment.
+func (dv *DetailView) attachmentAction(action string, app *App) tea.Cmd {
+	att := dv.selectedAttachment()
+	if att == nil {
+		return nil
+	}
+	a := *att
+	key := dv.issue.Key
+	dir := app.cfg.DownloadDir()
+	app.flashMsg = fmt.Sprintf("Downloading %s...", a.Filename)
+	return func() tea.Msg {
+		path, err := downloadAttachment(app.client, a, dir)
+		if err != nil {
+			return attachmentDoneMsg{issueKey: key, err: err}
+		}
+		if action == "open" {
+			// xdg-open / open hand local files to the default application
+			if err := openBrowser(path); err != nil {
+				return attachmentDoneMsg{issueKey: key, err: err}
+			}
+			return attachmentDoneMsg{issueKey: key, text: "Opened " + path}
+		}
+		return attachmentDoneMsg{issueKey: key, text: "Saved " + path}
+	}
+}
+
+// uploadAttachment attaches a local file and refreshes the cached issue.
+func (dv *DetailView) uploadAttachment(path string, app *App) tea.Cmd {
+	key := dv.issue.Key
+	path = expandHome(path)
+	app.flashMsg = fmt.Sprintf("Uploading %s...", filepath.Base(path))
+	return func() tea.Msg {
+		if _, err := app.client.UploadAttachment(key, path); err != nil {
+			return attachmentDoneMsg{issueKey: key, err: err}
+		}
+		if err := refreshIssue(app, key); err != nil {
+			return attachmentDoneMsg{issueKey: key, err: err}
+		}
+		return attachmentDoneMsg{issueKey: key, text: "Uploaded " + filepath.Base(path)}
+	}
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// completePath completes a partially typed file path like a shell would: a
+// unique match is filled in (with "/" for directories), otherwise the common
+// prefix is filled in and the candidates are returned for display.
+func completePath(input string) (string, []string) {
+	expanded := expandHome(input)
+	dir, prefix := filepath.Split(expanded)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return input, nil
+	}
+
+	var matches []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		if e.IsDir() {
+			name += "/"
+		}
+		matches = append(matches, name)
+	}
+	sort.Strings(matches)
+	if len(matches) == 0 {
+		return input, nil
+	}
+
+	common := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	// Keep a leading "~" as typed instead of showing the expanded home dir
+	completed := dir + common
+	if strings.HasPrefix(input, "~") {
+		home, _ := os.UserHomeDir()
+		completed = "~" + strings.TrimPrefix(completed, home)
+	}
+	if len(matches) == 1 {
+		return completed, nil
+	}
+	return completed, matches
+}
+
+// imageProtocol returns the inline image protocol the terminal supports:
+// "kitty", "sixel" or "" when images can't be shown.
+func imageProtocol() string {
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	if os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || termProgram == "ghostty" || termProgram == "WezTerm" {
+		return "kitty"
+	}
+	if term == "foot" || term == "mlterm" || strings.Contains(term, "sixel") {
+		// Encoding sixel is delegated to libsixel's img2sixel
+		if _, err := exec.LookPath("img2sixel"); err == nil {
+			return "sixel"
+		}
+	}
+	return ""
+}
+
+// imagePreview draws an image full-screen while the TUI is suspended, then
+// waits for Enter. It implements tea.ExecCommand.
+type imagePreview struct {
+	path     string
+	protocol string
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+func (p *imagePreview) SetStdin(r io.Reader)  { p.stdin = r }
+func (p *imagePreview) SetStdout(w io.Writer) { p.stdout = w }
+func (p *imagePreview) SetStderr(w io.Writer) { p.stderr = w }
+
+func (p *imagePreview) Run() error {
+	fmt.Fprint(p.stdout, "\x1b[2J\x1b[H")
+	var err error
+	switch p.protocol {
+	case "kitty":
+		err = writeKittyImage(p.stdout, p.path)
+	case "sixel":
+		cmd := exec.Command("img2sixel", p.path)
+		cmd.Stdout = p.stdout
+		cmd.Stderr = p.stderr
+		err = cmd.Run()
+	}
+	if err != nil {
+		fmt.Fprintf(p.stdout, "\r\nPreview failed: %v", err)
+	}
+	fmt.Fprint(p.stdout, "\r\n\r\nPress Enter to return")
+	bufio.NewReader(p.stdin).ReadString('\n')
+	if p.protocol == "kitty" {
+		fmt.Fprint(p.stdout, "\x1b_Ga=d\x1b\\") // delete the image
+	}
+	return nil
+}
+
+// writeKittyImage sends an image using the kitty graphics protocol, which
+// takes base64 PNG data in chunks of at most 4096 bytes.
+func writeKittyImage(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	for first := true; len(data) > 0; first = false {
+		n := min(4096, len(data))
+		chunk := data[:n]
+		data = data[n:]
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(w, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return nil
+}
+
+// previewAttachment downloads an image to a temp file in the background;
+// showPreview then shows it inline.
+func (dv *DetailView) previewAttachment(app *App) tea.Cmd {
+	att := dv.selectedAttachment()
+	if att == nil {
+		return nil
+	}
+	if !att.IsImage() {
+		app.flashMsg = "Only images can be previewed (Enter opens the file)"
+		return nil
+	}
+	protocol := imageProtocol()
+	if protocol == "" {
+		app.flashMsg = "Terminal doesn't support inline images (Enter opens the file)"
+		return nil
+	}
+
+	key, id := dv.issue.Key, att.ID
+	ext := filepath.Ext(att.Filename)
+	app.flashMsg = fmt.Sprintf("Downloading %s...", att.Filename)
+	return func() tea.Msg {
+		f, err := os.CreateTemp("", "shinkansen-*"+ext)
+		if err != nil {
+			return previewReadyMsg{issueKey: key, err: err}
+		}
+		path := f.Name()
+		err = app.client.DownloadAttachment(id, f)
+		f.Close()
+		if err != nil {
+			os.Remove(path)
+			return previewReadyMsg{issueKey: key, err: err}
+		}
+		return previewReadyMsg{issueKey: key, path: path, protocol: protocol}
+	}
+}
+
+// showPreview shows a downloaded image, unless the user left the issue
+// while it downloaded.
+func (a *App) showPreview(msg previewReadyMsg) tea.Cmd {
+	if msg.err != nil {
+		a.flashMsg = fmt.Sprintf("Preview failed: %v", msg.err)
+		return nil
+	}
+	if a.currentView != viewDetail || a.detail.issue == nil || a.detail.issue.Key != msg.issueKey {
+		os.Remove(msg.path)
+		return nil
+	}
+	a.flashMsg = ""
+	path := msg.path
+	return tea.Exec(&imagePreview{path: path, protocol: msg.protocol}, func(err error) tea.Msg {
+		os.Remove(path)
+		return attachmentDoneMsg{issueKey: msg.issueKey, err: err}
+	})
+}
+
+func (dv DetailView) renderAttachments(width int) []string {
+	var lines []string
+	atts := dv.issue.Fields.Attachments
+	if len(atts) == 0 {
+		lines = append(lines, helpDescStyle.Render("No attachments"))
+	}
+	for i, a := range atts {
+		line := fmt.Sprintf("%-40s %9s  %-20s %s",
+			truncate(a.Filename, 40),
+			formatSize(a.Size),
+			truncate(a.Author.DisplayName, 20),
+			a.Created[:min(len(a.Created), 10)],
+		)
+		line = truncate(line, width-6)
+		if i == dv.attachCursor {
+			line = selectedStyle.Width(width - 6).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if dv.uploading {
+		lines = append(lines, "")
+		lines = append(lines, searchPromptStyle.Render("Upload file: ")+dv.uploadBuf+"█")
+		if len(dv.uploadMatches) > 0 {
+			shown := dv.uploadMatches
+			if len(shown) > 8 {
+				shown = append(shown[:8:8], fmt.Sprintf("+%d more", len(dv.uploadMatches)-8))
+			}
+			lines = append(lines, helpDescStyle.Render("  "+strings.Join(shown, "  ")))
+		}
+	}
+	return lines
+}
+
+// formatSize renders a byte count in human-readable units.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
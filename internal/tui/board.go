@@ -118,11 +118,18 @@ func (bv BoardView) Update(msg tea.Msg, app *App) (BoardView, tea.Cmd) {
 				return bv, app.showTransitions(issue.Key)
 			}
 		case "t":
-			if issue := bv.SelectedIssue(); issue != nil {
+			if issue := bv.SelectedIssue(); issue != nil && !app.blocked("log", issue.Key) {
 				app.currentView = viewDetail
 				app.detail.SetIssue(issue)
 				app.detail.StartLogTime()
 			}
+		case "s":
+			if issue := bv.SelectedIssue(); issue != nil {
+				return bv, app.toggleTimer(issue.Key)
+			}
+		case "X", "M":
+			action := map[string]string{"X": "delete", "M": "move"}[msg.String()]
+			return bv, app.startManage(action, bv.SelectedIssue())
 		}
 	}
 	return bv, nil
@@ -173,13 +180,32 @@ func (bv BoardView) View(width, height int, active bool, selections map[string]b
 			}
 
 			line := fmt.Sprintf(" %s %s", check, issue.Key)
-			if len(issue.Fields.Summary) > colWidth-16 {
-				line += " " + issue.Fields.Summary[:colWidth-19] + "..."
+			selected := ci == bv.colCursor && ri == bv.rowCursor
+			maxSum := colWidth - 16
+			if _, total := issue.SubtaskProgress(); total > 0 {
+				maxSum -= 6
+			}
+			chips := ""
+			if names := issueChips(&issue); len(names) > 0 && !selected && maxSum > 30 {
+				chips = renderChips(names, maxSum/3)
+				maxSum -= lipgloss.Width(chips) + 1
+			}
+			if len(issue.Fields.Summary) > maxSum {
+				line += " " + issue.Fields.Summary[:maxSum-3] + "..."
 			} else {
 				line += " " + issue.Fields.Summary
 			}
+			if chips != "" {
+				line += " " + chips
+			}
+			if progress := subtaskProgressText(&issue); progress != "" {
+				if !selected {
+					progress = subtaskProgress(&issue, 0)
+				}
+				line += " " + progress
+			}
 
-			if ci == bv.colCursor && ri == bv.rowCursor {
+			if selected {
 				line = selectedStyle.Width(colWidth).Render(line)
 			}
 			rows = append(rows, line)
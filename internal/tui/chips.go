@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// chip renders a value as a coloured tag.
+func chip(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return chipStyle.Background(chipColors[h.Sum32()%uint32(len(chipColors))]).Render(name)
+}
+
+// renderChips renders values as chips that fit in maxWidth cells, ending
+// with "+N" when some don't fit.
+func renderChips(names []string, maxWidth int) string {
+	var parts []string
+	used := 0
+	for i, n := range names {
+		w := lipgloss.Width(n) + 3 // padding plus separator
+		// Keep room for the "+N" marker unless this is the last chip
+		reserve := 0
+		if i < len(names)-1 {
+			reserve = 4
+		}
+		if used+w+reserve > maxWidth {
+			parts = append(parts, helpDescStyle.Render(fmt.Sprintf("+%d", len(names)-i)))
+			break
+		}
+		parts = append(parts, chip(n))
+		used += w
+	}
+	return strings.Join(parts, " ")
+}
+
+// issueChips returns the labels and components shown next to an issue in
+// the list and board.
+func issueChips(issue *jira.Issue) []string {
+	return append(append([]string{}, issue.Fields.Labels...), issue.Fields.ComponentNames()...)
+}
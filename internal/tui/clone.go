@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// issueActionMsg is sent after an action that created or changed issues,
+// such as a clone, a split or a subtask change.
+type issueActionMsg struct {
+	issueKey string
+	text     string
+	err      error
+}
+
+// Rows of the clone form.
+const (
+	cloneRowProject = iota
+	cloneRowLinks
+	cloneRowSubtasks
+	cloneRowCount
+)
+
+// CloneForm asks where to clone an issue and what to copy along.
+type CloneForm struct {
+	visible     bool
+	issueKey    string
+	srcProject  string
+	hasSubtasks bool
+	project     string
+	links       bool
+	subtasks    bool
+	row         int
+}
+
+// Show opens the form for cloning an issue into its own project.
+func (cf *CloneForm) Show(issue *jira.Issue) {
+	*cf = CloneForm{
+		visible:     true,
+		issueKey:    issue.Key,
+		srcProject:  issue.Fields.Project.Key,
+		hasSubtasks: len(issue.Fields.Subtasks) > 0,
+		project:     issue.Fields.Project.Key,
+	}
+}
+
+func (cf CloneForm) Update(msg tea.KeyMsg, app *App) (CloneForm, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		cf.visible = false
+	case "tab", "down":
+		cf.row = (cf.row + 1) % cloneRowCount
+	case "shift+tab", "up":
+		cf.row = (cf.row - 1 + cloneRowCount) % cloneRowCount
+	case "enter":
+		project := strings.TrimSpace(cf.project)
+		if project == "" {
+			return cf, nil
+		}
+		cf.visible = false
+		key := cf.issueKey
+		opts := jira.CloneOptions{Project: project, Links: cf.links, Subtasks: cf.subtasks}
+		otherProject := cf.subtasks && cf.hasSubtasks && project != cf.srcProject
+		app.flashMsg = fmt.Sprintf("Cloning %s...", key)
+		run := func() tea.Msg {
+			// Sub-task types are per project; use the target's
+			if otherProject {
+				t, err := cache.SubtaskType(app.client, app.store, project)
+				if err != nil {
+					return issueActionMsg{issueKey: key, err: err}
+				}
+				opts.SubtaskType = t
+			}
+			clone, err := app.client.CloneIssue(key, opts)
+			if clone == nil {
+				return issueActionMsg{issueKey: key, err: err}
+			}
+			text := fmt.Sprintf("Cloned %s as %s", key, clone.Key)
+			if err != nil {
+				return issueActionMsg{issueKey: key, err: fmt.Errorf("%s, but %v", text, err)}
+			}
+			return issueActionMsg{issueKey: key, text: text}
+		}
+		app.syncing = true
+		return cf, tea.Sequence(run, app.syncCmd())
+	case "backspace":
+		if cf.row == cloneRowProject && len(cf.project) > 0 {
+			cf.project = cf.project[:len(cf.project)-1]
+		}
+	case " ":
+		switch cf.row {
+		case cloneRowLinks:
+			cf.links = !cf.links
+		case cloneRowSubtasks:
+			cf.subtasks = !cf.subtasks
+		}
+	default:
+		if ch := msg.String(); cf.row == cloneRowProject && len(ch) == 1 {
+			cf.project += strings.ToUpper(ch)
+		}
+	}
+	return cf, nil
+}
+
+func (cf CloneForm) View(width, height int) string {
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	rows := []struct{ name, value string }{
+		{"Project", cf.project},
+		{"Copy links", check(cf.links)},
+		{"Copy subtasks", check(cf.subtasks)},
+	}
+
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render("Clone "+cf.issueKey))
+	lines = append(lines, "")
+	for i, r := range rows {
+		label := fmt.Sprintf("  %-15s", r.name+":")
+		value := r.value
+		if i == cf.row {
+			label = searchPromptStyle.Render(fmt.Sprintf("> %-15s", r.name+":"))
+			if i == cloneRowProject {
+				value += "█"
+			}
+		}
+		lines = append(lines, label+" "+value)
+	}
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("  Copies summary, description, type, priority, labels and components"))
+	lines = append(lines, helpDescStyle.Render("  and links the clone to "+cf.issueKey+"."))
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("  Tab/↑↓: field  Space: toggle  Enter: clone  Esc: cancel"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 70)).Render(strings.Join(lines, "\n")),
+	)
+}
+
+// SplitForm turns the checklist items of an issue's description into child
+// issues, with the unchecked items selected.
+type SplitForm struct {
+	visible  bool
+	issue    *jira.Issue
+	items    []jira.ChecklistItem
+	selected []bool
+	cursor   int
+}
+
+// Show opens the form for an issue. It returns false when the description
+// has no checklist items.
+func (sf *SplitForm) Show(issue *jira.Issue) bool {
+	items := issue.ChecklistItems()
+	if len(items) == 0 {
+		return false
+	}
+	*sf = SplitForm{visible: true, issue: issue, items: items, selected: make([]bool, len(items))}
+	for i, item := range items {
+		sf.selected[i] = !item.Done
+	}
+	return true
+}
+
+func (sf SplitForm) Update(msg tea.KeyMsg, app *App) (SplitForm, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		sf.visible = false
+	case "down":
+		if sf.cursor < len(sf.items)-1 {
+			sf.cursor++
+		}
+	case "up":
+		if sf.cursor > 0 {
+			sf.cursor--
+		}
+	case " ":
+		sf.selected[sf.cursor] = !sf.selected[sf.cursor]
+	case "a":
+		all := true
+		for _, s := range sf.selected {
+			all = all && s
+		}
+		for i := range sf.selected {
+			sf.selected[i] = !all
+		}
+	case "enter":
+		var summaries []string
+		for i, item := range sf.items {
+			if sf.selected[i] {
+				summaries = append(summaries, item.Text)
+			}
+		}
+		if len(summaries) == 0 {
+			return sf, nil
+		}
+		sf.visible = false
+		app.flashMsg = fmt.Sprintf("Creating %d child issues...", len(summaries))
+		return sf, sf.split(summaries, app)
+	}
+	return sf, nil
+}
+
+// split creates one child per summary: sub-tasks under a standard issue,
+// tasks under an epic.
+func (sf SplitForm) split(summaries []string, app *App) tea.Cmd {
+	parent := sf.issue
+	split := func() tea.Msg {
+		project := parent.Fields.Project.Key
+		var issueType string
+		switch {
+		case parent.Fields.IssueType.Subtask:
+			return issueActionMsg{issueKey: parent.Key, err: fmt.Errorf("sub-tasks can't have children")}
+		case parent.Fields.IssueType.HierarchyLevel >= 1 || parent.Fields.IssueType.Name == "Epic":
+			issueType = "Task"
+		default:
+			t, err := cache.SubtaskType(app.client, app.store, project)
+			if err != nil {
+				return issueActionMsg{issueKey: parent.Key, err: err}
+			}
+			issueType = t
+		}
+
+		var keys []string
+		var err error
+		for _, summary := range summaries {
+			extra := map[string]interface{}{"parent": map[string]string{"key": parent.Key}}
+			var child *jira.Issue
+			if child, err = app.client.CreateIssueWithDetails(project, summary, issueType, "", "", extra); err != nil {
+				break
+			}
+			keys = append(keys, child.Key)
+		}
+		if rerr := refreshIssue(app, parent.Key); err == nil {
+			err = rerr
+		}
+		text := fmt.Sprintf("Split %s into %s", parent.Key, strings.Join(keys, ", "))
+		if err != nil {
+			if len(keys) > 0 {
+				err = fmt.Errorf("%s, then failed: %v", text, err)
+			}
+			return issueActionMsg{issueKey: parent.Key, err: err}
+		}
+		return issueActionMsg{issueKey: parent.Key, text: text}
+	}
+	app.syncing = true
+	return tea.Sequence(split, app.syncCmd())
+}
+
+func (sf SplitForm) View(width, height int) string {
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render("Split "+sf.issue.Key+" into child issues"))
+	lines = append(lines, "")
+	for i, item := range sf.items {
+		box := "[ ]"
+		if sf.selected[i] {
+			box = "[x]"
+		}
+		text := item.Text
+		if item.Done {
+			text += helpDescStyle.Render("  (done)")
+		}
+		line := "  " + box + " " + text
+		if i == sf.cursor {
+			line = selectedStyle.Render("> " + box + " " + item.Text)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("  Space: toggle  a: all/none  Enter: create  Esc: cancel"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 90)).Render(strings.Join(lines, "\n")),
+	)
+}
@@ -1,11 +1,16 @@
 package tui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
 // createDoneMsg is sent after a new issue is created successfully.
@@ -15,52 +20,308 @@ type createDoneMsg struct {
 
 // createErrMsg is sent when issue creation fails.
 type createErrMsg struct {
-	err error
+	err     error
+	created bool // the issue was created, and only something after it failed
 }
 
-// createField identifies which field is being edited.
-type createField int
+// createProjectsMsg carries the projects the form can create issues in.
+type createProjectsMsg struct {
+	projects []jira.Project
+}
+
+// createTypesMsg carries a project's creatable issue types from createmeta.
+type createTypesMsg struct {
+	projectKey string
+	types      []jira.IssueType
+	err        error
+}
+
+// createFieldsMsg carries the create screen of one issue type.
+type createFieldsMsg struct {
+	projectKey  string
+	issueTypeID string
+	fields      []jira.CreateField
+	err         error
+}
 
+// Fixed rows at the top of the form; template placeholders and then
+// createmeta fields follow them.
 const (
-	fieldSummary createField = iota
-	fieldType
-	fieldPriority
-	fieldDescription
-	fieldCount // sentinel: total number of fields
+	rowTemplate = iota
+	rowProject
+	rowType
+	rowSummary
+	rowParent
+	fixedRows
 )
 
-// issueTypes available for creation.
+// issueTypes and issuePriorities are used when createmeta can't be loaded
+// (offline with nothing cached).
 var issueTypes = []string{"Task", "Bug", "Story"}
-
-// issuePriorities available for creation.
 var issuePriorities = []string{"Highest", "High", "Medium", "Low", "Lowest"}
 
-// CreateView handles the multi-field issue creation form.
+// skippedFields are createmeta fields with their own row or that the form
+// doesn't offer.
+var skippedFields = map[string]bool{
+	"project": true, "issuetype": true, "summary": true, "parent": true,
+	"reporter": true, "attachment": true, "issuelinks": true,
+}
+
+// inputKind is how a createmeta field is edited, derived from its schema.
+type inputKind int
+
+const (
+	inputText      inputKind = iota // string, number, date, datetime, user
+	inputMultiline                  // description and text areas
+	inputChoice                     // single value from allowed values
+	inputMulti                      // several values: allowed values or labels
+	inputList                       // free-form array, comma separated
+)
+
+// formField is one createmeta field and the value entered for it.
+type formField struct {
+	meta   jira.CreateField
+	text   string
+	choice int      // index into meta.AllowedValues, -1 = none
+	values []string // selected allowed values, or labels
+}
+
+func (f *formField) kind() inputKind {
+	s := f.meta.Schema
+	switch {
+	case s.System == "description" || strings.HasSuffix(s.Custom, ":textarea"):
+		return inputMultiline
+	case s.Type == "array" && (len(f.meta.AllowedValues) > 0 || s.System == "labels"):
+		return inputMulti
+	case s.Type == "array":
+		return inputList
+	case len(f.meta.AllowedValues) > 0:
+		return inputChoice
+	}
+	return inputText
+}
+
+func (f *formField) empty() bool {
+	switch f.kind() {
+	case inputChoice:
+		return f.choice < 0
+	case inputMulti:
+		return len(f.values) == 0
+	}
+	return strings.TrimSpace(f.text) == ""
+}
+
+// validate checks the entered value's format; required fields are checked
+// by the form.
+func (f *formField) validate() error {
+	if f.empty() || f.kind() != inputText {
+		return nil
+	}
+	text := strings.TrimSpace(f.text)
+	switch f.meta.Schema.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return fmt.Errorf("%s must be a number", f.meta.Name)
+		}
+	case "date":
+		if _, err := time.ParseInLocation("2006-01-02", text, time.Local); err != nil {
+			return fmt.Errorf("%s must be a date like 2024-03-31", f.meta.Name)
+		}
+	case "datetime":
+		if _, err := time.ParseInLocation("2006-01-02 15:04", text, time.Local); err != nil {
+			return fmt.Errorf("%s must be like 2024-03-31 14:00", f.meta.Name)
+		}
+	}
+	return nil
+}
+
+// payload converts the entered value to Jira's create format. Users are
+// looked up by name, so this talks to Jira and must run inside a tea.Cmd.
+func (f *formField) payload(client *jira.Client, accountID string) (interface{}, error) {
+	text := strings.TrimSpace(f.text)
+	ref := func(v jira.AllowedValue) map[string]string {
+		if v.ID == "" {
+			return map[string]string{"name": v.Label()} // fallback values have no ID
+		}
+		return map[string]string{"id": v.ID}
+	}
+
+	switch f.kind() {
+	case inputMultiline:
+		if f.meta.Schema.System == "description" {
+			return text, nil // passed as CreateIssueWithDetails' description
+		}
+		return jira.ADFDoc(text), nil
+	case inputChoice:
+		return ref(f.meta.AllowedValues[f.choice]), nil
+	case inputMulti:
+		if len(f.meta.AllowedValues) == 0 {
+			return f.values, nil // labels
+		}
+		var refs []map[string]string
+		for _, v := range f.meta.AllowedValues {
+			if contains(f.values, v.Label()) {
+				refs = append(refs, ref(v))
+			}
+		}
+		return refs, nil
+	case inputList:
+		var items []string
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+
+	switch f.meta.Schema.Type {
+	case "number":
+		return strconv.ParseFloat(text, 64)
+	case "datetime":
+		t, _ := time.ParseInLocation("2006-01-02 15:04", text, time.Local)
+		return t.Format("2006-01-02T15:04:05.000-0700"), nil
+	case "user":
+		if text == "me" {
+			return map[string]string{"accountId": accountID}, nil
+		}
+		users, err := client.SearchUsers(text)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if strings.EqualFold(u.DisplayName, text) || strings.EqualFold(u.EmailAddress, text) {
+				return map[string]string{"accountId": u.AccountID}, nil
+			}
+		}
+		if len(users) != 1 {
+			return nil, fmt.Errorf("%s: %d users match %q", f.meta.Name, len(users), text)
+		}
+		return map[string]string{"accountId": users[0].AccountID}, nil
+	}
+	return text, nil
+}
+
+// display renders the field's current value for the form.
+func (f *formField) display(focused bool) string {
+	switch f.kind() {
+	case inputChoice:
+		if f.choice < 0 {
+			return helpDescStyle.Render("(none)")
+		}
+		v := f.meta.AllowedValues[f.choice].Label()
+		if focused {
+			return selectedStyle.Render(" ‹ "+v+" › ") + helpDescStyle.Render(fmt.Sprintf("  %d/%d", f.choice+1, len(f.meta.AllowedValues)))
+		}
+		return v
+	case inputMulti:
+		v := renderChips(f.values, 50)
+		if v == "" {
+			v = helpDescStyle.Render("(none)")
+		}
+		if focused {
+			v += helpDescStyle.Render("  Enter: choose")
+		}
+		return v
+	}
+	v := f.text
+	if focused {
+		v += "█"
+	} else if v == "" {
+		v = helpDescStyle.Render(f.hint())
+	}
+	return v
+}
+
+// hint describes the expected input of an empty text field.
+func (f *formField) hint() string {
+	switch f.meta.Schema.Type {
+	case "number":
+		return "(number)"
+	case "date":
+		return "(YYYY-MM-DD)"
+	case "datetime":
+		return "(YYYY-MM-DD HH:MM)"
+	case "user":
+		return "(name, email or \"me\")"
+	case "array":
+		return "(comma separated)"
+	}
+	return ""
+}
+
+// fallbackFields mirrors the form's original fixed fields, for when the
+// create screen can't be loaded.
+func fallbackFields() []jira.CreateField {
+	var prios []jira.AllowedValue
+	for _, p := range issuePriorities {
+		prios = append(prios, jira.AllowedValue{Name: p})
+	}
+	return []jira.CreateField{
+		{FieldID: "priority", Name: "Priority", Schema: jira.FieldSchema{Type: "priority", System: "priority"}, AllowedValues: prios},
+		{FieldID: "labels", Name: "Labels", Schema: jira.FieldSchema{Type: "array", Items: "string", System: "labels"}},
+		{FieldID: "description", Name: "Description", Schema: jira.FieldSchema{Type: "string", System: "description"}},
+	}
+}
+
+// templateVar is a template placeholder and the value entered for it.
+type templateVar struct {
+	name  string
+	value string
+}
+
+// CreateView handles the issue creation form. Its fields come from the
+// project's create screen (createmeta) for the chosen issue type.
 type CreateView struct {
 	visible  bool
-	field    createField
+	row      int
+	projects []string
+	project  string
+	types    []jira.IssueType
+	typeIdx  int
 	summary  string
-	typeIdx  int // index into issueTypes
-	prioIdx  int // index into issuePriorities
-	desc     string
+	parent   string
+	fields   []formField
+	loading  bool
 	errMsg   string
+	metaErr  string // createmeta couldn't be loaded; basic fields are shown
+
+	templates   []config.Template
+	tmplIdx     int // index into templates, -1 = none
+	vars        []templateVar
+	tmplPending bool // apply the template to the fields once they load
 }
 
 func NewCreateView() CreateView {
-	return CreateView{
-		prioIdx: 2, // default to Medium
-	}
+	return CreateView{}
 }
 
-// Show opens the create form with default values.
-func (cv *CreateView) Show() {
+// Show opens the create form on the default project and loads its issue types.
+func (cv *CreateView) Show(app *App) tea.Cmd {
 	cv.visible = true
-	cv.field = fieldSummary
+	cv.row = rowSummary
+	cv.project = app.cfg.DefaultProject
+	cv.projects = []string{cv.project}
 	cv.summary = ""
-	cv.typeIdx = 0
-	cv.prioIdx = 2
-	cv.desc = ""
+	cv.parent = ""
+	cv.fields = nil
 	cv.errMsg = ""
+	cv.metaErr = ""
+	cv.tmplIdx = -1
+	cv.vars = nil
+	cv.tmplPending = false
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		cv.metaErr = err.Error()
+	}
+	cv.templates = templates
+	return tea.Batch(cv.loadTypes(app), func() tea.Msg {
+		projects, err := app.client.GetProjects()
+		if err != nil {
+			return nil
+		}
+		return createProjectsMsg{projects: projects}
+	})
 }
 
 // Hide closes the create form.
@@ -68,6 +329,403 @@ func (cv *CreateView) Hide() {
 	cv.visible = false
 }
 
+// setProjects offers the fetched projects in the project selector.
+func (cv *CreateView) setProjects(projects []jira.Project) {
+	cv.projects = []string{cv.project}
+	for _, p := range projects {
+		if p.Key != cv.project {
+			cv.projects = append(cv.projects, p.Key)
+		}
+	}
+}
+
+func (cv *CreateView) loadTypes(app *App) tea.Cmd {
+	cv.types = nil
+	cv.typeIdx = 0
+	cv.loading = true
+	project := cv.project
+	return tea.Batch(func() tea.Msg {
+		types, err := cache.CreateIssueTypes(app.client, app.store, project)
+		return createTypesMsg{projectKey: project, types: types, err: err}
+	}, app.loadPermissions(project))
+}
+
+func (cv *CreateView) loadFields(app *App) tea.Cmd {
+	if cv.typeIdx >= len(cv.types) {
+		return nil
+	}
+	cv.loading = true
+	project := cv.project
+	typeID := cv.types[cv.typeIdx].ID
+	if typeID == "" {
+		// Fallback type list: there is no create screen to ask for
+		return func() tea.Msg {
+			return createFieldsMsg{projectKey: project, fields: fallbackFields()}
+		}
+	}
+	return func() tea.Msg {
+		fields, err := cache.CreateFields(app.client, app.store, project, typeID)
+		return createFieldsMsg{projectKey: project, issueTypeID: typeID, fields: fields, err: err}
+	}
+}
+
+// setTypes is called when the issue types of the form's project arrive.
+func (cv *CreateView) setTypes(msg createTypesMsg, app *App) tea.Cmd {
+	if msg.projectKey != cv.project {
+		return nil
+	}
+	cv.metaErr = ""
+	cv.types = msg.types
+	if msg.err != nil || len(cv.types) == 0 {
+		if msg.err != nil {
+			cv.metaErr = fmt.Sprintf("Create screen unavailable (%v); using basic fields", msg.err)
+		}
+		cv.types = nil
+		for _, name := range issueTypes {
+			cv.types = append(cv.types, jira.IssueType{Name: name})
+		}
+	}
+	cv.typeIdx = 0
+	if t := cv.template(); t != nil {
+		cv.selectType(t.Type)
+	}
+	return cv.loadFields(app)
+}
+
+// setFields rebuilds the createmeta rows, keeping values entered for fields
+// that also exist on the new screen.
+func (cv *CreateView) setFields(msg createFieldsMsg) {
+	if msg.projectKey != cv.project || cv.typeIdx >= len(cv.types) || msg.issueTypeID != cv.types[cv.typeIdx].ID {
+		return
+	}
+	cv.loading = false
+	metas := msg.fields
+	if msg.err != nil {
+		cv.metaErr = fmt.Sprintf("Create screen unavailable (%v); using basic fields", msg.err)
+		metas = fallbackFields()
+	}
+
+	old := make(map[string]formField)
+	for _, f := range cv.fields {
+		old[f.meta.FieldID] = f
+	}
+	// Required fields first, createmeta order otherwise
+	var required, optional []formField
+	for _, m := range metas {
+		if skippedFields[m.FieldID] {
+			continue
+		}
+		f := formField{meta: m, choice: -1}
+		if prev, ok := old[m.FieldID]; ok {
+			f.text, f.values = prev.text, prev.values
+			if prev.choice < len(m.AllowedValues) {
+				f.choice = prev.choice
+			}
+		}
+		if f.kind() == inputChoice && f.choice < 0 && m.Schema.System == "priority" {
+			f.choice = len(m.AllowedValues) / 2 // middle priority, usually Medium
+		}
+		if m.Required && !m.HasDefaultValue {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+	cv.fields = append(required, optional...)
+	if cv.tmplPending {
+		cv.applyTemplateFields()
+	}
+	if cv.row >= cv.rowCount() {
+		cv.row = rowSummary
+	}
+}
+
+// template returns the selected template, if any.
+func (cv *CreateView) template() *config.Template {
+	if cv.tmplIdx < 0 || cv.tmplIdx >= len(cv.templates) {
+		return nil
+	}
+	return &cv.templates[cv.tmplIdx]
+}
+
+// selectType picks the issue type with the given name, if the project has it.
+func (cv *CreateView) selectType(name string) bool {
+	for i, t := range cv.types {
+		if strings.EqualFold(t.Name, name) {
+			cv.typeIdx = i
+			return true
+		}
+	}
+	return false
+}
+
+// applyTemplate fills the form from the selected template: project, type,
+// summary pattern and placeholder rows now, the other fields once the
+// template's create screen has loaded.
+func (cv *CreateView) applyTemplate(app *App) tea.Cmd {
+	t := cv.template()
+	old := make(map[string]string)
+	for _, v := range cv.vars {
+		old[v.name] = v.value
+	}
+	cv.vars = nil
+	if t == nil {
+		return nil
+	}
+	for _, name := range t.Placeholders() {
+		cv.vars = append(cv.vars, templateVar{name: name, value: old[name]})
+	}
+	cv.summary = t.Summary
+	cv.tmplPending = true
+
+	if t.Project != "" && t.Project != cv.project {
+		cv.project = t.Project
+		if !contains(cv.projects, t.Project) {
+			cv.projects = append(cv.projects, t.Project)
+		}
+		cv.fields = nil
+		return cv.loadTypes(app)
+	}
+	prev := cv.typeIdx
+	if cv.selectType(t.Type) && cv.typeIdx != prev {
+		return cv.loadFields(app)
+	}
+	if !cv.loading {
+		cv.applyTemplateFields()
+	}
+	return nil
+}
+
+// applyTemplateFields sets priority, labels, components and description
+// from the selected template.
+func (cv *CreateView) applyTemplateFields() {
+	cv.tmplPending = false
+	t := cv.template()
+	if t == nil {
+		return
+	}
+	for i := range cv.fields {
+		f := &cv.fields[i]
+		switch f.meta.Schema.System {
+		case "priority":
+			for j, v := range f.meta.AllowedValues {
+				if t.Priority != "" && strings.EqualFold(v.Label(), t.Priority) {
+					f.choice = j
+				}
+			}
+		case "labels":
+			if len(t.Labels) > 0 {
+				f.values = append([]string{}, t.Labels...)
+			}
+		case "components":
+			if len(t.Components) > 0 {
+				f.values = append([]string{}, t.Components...)
+			}
+		case "description":
+			if t.Description != "" {
+				f.text = t.Description
+			}
+		}
+	}
+}
+
+// varValues returns the placeholder values entered in the form.
+func (cv *CreateView) varValues() map[string]string {
+	values := make(map[string]string)
+	for _, v := range cv.vars {
+		values[v.name] = strings.TrimSpace(v.value)
+	}
+	return values
+}
+
+// setValues stores the values chosen in the field picker for a field.
+func (cv *CreateView) setValues(fieldID string, values []string) {
+	for i := range cv.fields {
+		if cv.fields[i].meta.FieldID == fieldID {
+			cv.fields[i].values = values
+		}
+	}
+}
+
+func (cv *CreateView) rowCount() int {
+	return fixedRows + len(cv.vars) + len(cv.fields)
+}
+
+// focusedVar returns the template placeholder under the cursor, if any.
+func (cv *CreateView) focusedVar() *templateVar {
+	if i := cv.row - fixedRows; i >= 0 && i < len(cv.vars) {
+		return &cv.vars[i]
+	}
+	return nil
+}
+
+// focusedField returns the createmeta field under the cursor, if any.
+func (cv *CreateView) focusedField() *formField {
+	i := cv.row - fixedRows - len(cv.vars)
+	if i < 0 || i >= len(cv.fields) {
+		return nil
+	}
+	return &cv.fields[i]
+}
+
+func (cv *CreateView) issueType() jira.IssueType {
+	if cv.typeIdx < len(cv.types) {
+		return cv.types[cv.typeIdx]
+	}
+	return jira.IssueType{Name: issueTypes[0]}
+}
+
+// validate checks required fields and value formats before submitting.
+func (cv *CreateView) validate() error {
+	if strings.TrimSpace(cv.summary) == "" {
+		return fmt.Errorf("Summary is required")
+	}
+	if cv.issueType().Subtask && strings.TrimSpace(cv.parent) == "" {
+		return fmt.Errorf("Parent is required for %s", cv.issueType().Name)
+	}
+	for _, v := range cv.vars {
+		if strings.TrimSpace(v.value) == "" {
+			return fmt.Errorf("%s is required by the template", v.name)
+		}
+	}
+	for i := range cv.fields {
+		f := &cv.fields[i]
+		if f.meta.Required && !f.meta.HasDefaultValue && f.empty() {
+			return fmt.Errorf("%s is required", f.meta.Name)
+		}
+		if err := f.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submit creates the issue from the form's current values.
+func (cv *CreateView) submit(app *App) tea.Cmd {
+	projectKey := cv.project
+	values := cv.varValues()
+	summary := strings.TrimSpace(config.Expand(cv.summary, values))
+	issueType := cv.issueType().Name
+	parent := strings.ToUpper(strings.TrimSpace(cv.parent))
+	fields := append([]formField{}, cv.fields...)
+	for i := range fields {
+		fields[i].text = config.Expand(fields[i].text, values)
+	}
+	tmpl := cv.template()
+	accountID := app.cfg.AccountID
+
+	return func() tea.Msg {
+		var description string
+		extra := map[string]interface{}{}
+		if parent != "" {
+			extra["parent"] = map[string]string{"key": parent}
+		}
+		for i := range fields {
+			f := &fields[i]
+			if f.empty() {
+				continue
+			}
+			value, err := f.payload(app.client, accountID)
+			if err != nil {
+				return createErrMsg{err: err}
+			}
+			if f.meta.Schema.System == "description" {
+				description = value.(string)
+				continue
+			}
+			extra[f.meta.FieldID] = value
+		}
+
+		issue, err := app.client.CreateIssueWithDetails(projectKey, summary, issueType, "", description, extra)
+		if err != nil {
+			return createErrMsg{err: err}
+		}
+		if tmpl != nil && len(tmpl.Subtasks) > 0 {
+			if _, err := cache.CreateTemplateSubtasks(app.client, app.store, tmpl, projectKey, issue.Key, values); err != nil {
+				return createErrMsg{err: fmt.Errorf("created %s, but %v", issue.Key, err), created: true}
+			}
+		}
+
+		// Try to add to active sprint
+		if app.cfg.DefaultBoard > 0 && projectKey == app.cfg.DefaultProject {
+			sprints, err := app.client.GetSprints(app.cfg.DefaultBoard)
+			if err == nil {
+				for _, s := range sprints {
+					if s.State == "active" {
+						app.client.MoveToSprint(s.ID, issue.Key)
+						break
+					}
+				}
+			}
+		}
+
+		// The board is refreshed by the sync that createDoneMsg starts
+		return createDoneMsg{issueKey: issue.Key}
+	}
+}
+
+// cycle moves a selector row left (-1) or right (+1).
+func (cv *CreateView) cycle(delta int, app *App) tea.Cmd {
+	switch cv.row {
+	case rowTemplate:
+		if next := cv.tmplIdx + delta; next >= -1 && next < len(cv.templates) {
+			cv.tmplIdx = next
+			return cv.applyTemplate(app)
+		}
+	case rowProject:
+		for i, p := range cv.projects {
+			if p != cv.project {
+				continue
+			}
+			if next := i + delta; next >= 0 && next < len(cv.projects) {
+				cv.project = cv.projects[next]
+				cv.fields = nil
+				return cv.loadTypes(app)
+			}
+			break
+		}
+	case rowType:
+		if next := cv.typeIdx + delta; next >= 0 && next < len(cv.types) {
+			cv.typeIdx = next
+			return cv.loadFields(app)
+		}
+	default:
+		f := cv.focusedField()
+		if f == nil || f.kind() != inputChoice {
+			return nil
+		}
+		lowest := -1
+		if f.meta.Required {
+			lowest = 0
+		}
+		if next := f.choice + delta; next >= lowest && next < len(f.meta.AllowedValues) {
+			f.choice = next
+		}
+	}
+	return nil
+}
+
+// editText returns the text edited on the current row, or nil if the row
+// isn't a text input.
+func (cv *CreateView) editText() *string {
+	switch cv.row {
+	case rowSummary:
+		return &cv.summary
+	case rowParent:
+		return &cv.parent
+	}
+	if v := cv.focusedVar(); v != nil {
+		return &v.value
+	}
+	if f := cv.focusedField(); f != nil {
+		switch f.kind() {
+		case inputText, inputMultiline, inputList:
+			return &f.text
+		}
+	}
+	return nil
+}
+
 func (cv CreateView) Update(msg tea.Msg, app *App) (CreateView, tea.Cmd) {
 	if !cv.visible {
 		return cv, nil
@@ -81,117 +739,75 @@ func (cv CreateView) Update(msg tea.Msg, app *App) (CreateView, tea.Cmd) {
 			app.currentView = viewIssues
 			return cv, nil
 
-		case "tab":
+		case "tab", "down":
 			// Move to next field
-			cv.field = (cv.field + 1) % fieldCount
+			cv.row = (cv.row + 1) % cv.rowCount()
 			return cv, nil
 
-		case "shift+tab":
+		case "shift+tab", "up":
 			// Move to previous field
-			cv.field = (cv.field - 1 + fieldCount) % fieldCount
+			cv.row = (cv.row - 1 + cv.rowCount()) % cv.rowCount()
 			return cv, nil
 
 		case "ctrl+s", "ctrl+enter":
 			// Submit the form
-			if cv.summary == "" {
-				cv.errMsg = "Summary is required"
+			if cv.loading {
+				cv.errMsg = "Still loading the create screen"
+				return cv, nil
+			}
+			if err := cv.validate(); err != nil {
+				cv.errMsg = err.Error()
+				return cv, nil
+			}
+			if reason := app.denial("create", cv.project); reason != "" {
+				cv.errMsg = reason
 				return cv, nil
 			}
 			cv.errMsg = ""
-			summary := cv.summary
-			issueType := issueTypes[cv.typeIdx]
-			priority := issuePriorities[cv.prioIdx]
-			description := cv.desc
-			projectKey := app.cfg.DefaultProject
-
+			cmd := cv.submit(app)
 			cv.Hide()
 			app.currentView = viewIssues
 			app.flashMsg = "Creating issue..."
+			return cv, cmd
 
-			return cv, func() tea.Msg {
-				issue, err := app.client.CreateIssueWithDetails(projectKey, summary, issueType, priority, description)
-				if err != nil {
-					return createErrMsg{err: err}
-				}
-
-				// Try to add to active sprint
-				if app.cfg.DefaultBoard > 0 {
-					sprints, err := app.client.GetSprints(app.cfg.DefaultBoard)
-					if err == nil {
-						for _, s := range sprints {
-							if s.State == "active" {
-								app.client.MoveToSprint(s.ID, issue.Key)
-								break
-							}
-						}
+		case "enter":
+			if f := cv.focusedField(); f != nil {
+				switch f.kind() {
+				case inputMultiline:
+					f.text += "\n"
+					return cv, nil
+				case inputMulti:
+					if len(f.meta.AllowedValues) == 0 {
+						return cv, app.fieldPicker.ShowForForm(kindLabels, f.meta.FieldID, f.meta.Name, cv.project, nil, f.values, app)
 					}
+					var options []string
+					for _, v := range f.meta.AllowedValues {
+						options = append(options, v.Label())
+					}
+					return cv, app.fieldPicker.ShowForForm(kindOptions, f.meta.FieldID, f.meta.Name, cv.project, options, f.values, app)
 				}
-
-				// Sync to refresh the board
-				result := cache.Sync(app.client, app.store, app.cfg.DefaultProject)
-				if result.Err != nil {
-					return createDoneMsg{issueKey: issue.Key}
-				}
-				return createDoneMsg{issueKey: issue.Key}
-			}
-
-		case "enter":
-			// In description field, enter adds a newline; otherwise move to next field
-			if cv.field == fieldDescription {
-				cv.desc += "\n"
-				return cv, nil
 			}
 			// For other fields, treat as tab (next field)
-			cv.field = (cv.field + 1) % fieldCount
+			cv.row = (cv.row + 1) % cv.rowCount()
 			return cv, nil
 
 		case "left":
-			switch cv.field {
-			case fieldType:
-				if cv.typeIdx > 0 {
-					cv.typeIdx--
-				}
-			case fieldPriority:
-				if cv.prioIdx > 0 {
-					cv.prioIdx--
-				}
-			}
-			return cv, nil
+			return cv, cv.cycle(-1, app)
 
 		case "right":
-			switch cv.field {
-			case fieldType:
-				if cv.typeIdx < len(issueTypes)-1 {
-					cv.typeIdx++
-				}
-			case fieldPriority:
-				if cv.prioIdx < len(issuePriorities)-1 {
-					cv.prioIdx++
-				}
-			}
-			return cv, nil
+			return cv, cv.cycle(1, app)
 
 		case "backspace":
-			switch cv.field {
-			case fieldSummary:
-				if len(cv.summary) > 0 {
-					cv.summary = cv.summary[:len(cv.summary)-1]
-				}
-			case fieldDescription:
-				if len(cv.desc) > 0 {
-					cv.desc = cv.desc[:len(cv.desc)-1]
-				}
+			if t := cv.editText(); t != nil && len(*t) > 0 {
+				*t = (*t)[:len(*t)-1]
 			}
 			return cv, nil
 
 		default:
 			ch := msg.String()
 			if len(ch) == 1 || ch == " " {
-				switch cv.field {
-				case fieldSummary:
-					cv.summary += ch
-				case fieldDescription:
-					cv.desc += ch
+				if t := cv.editText(); t != nil {
+					*t += ch
 				}
 			}
 			return cv, nil
@@ -205,70 +821,108 @@ func (cv CreateView) View(width, height int) string {
 		return ""
 	}
 
-	var lines []string
-	lines = append(lines, detailHeaderStyle.Render("Create New Issue"))
-	lines = append(lines, "")
-
-	// Summary field
-	summaryLabel := "  Summary:"
-	if cv.field == fieldSummary {
-		summaryLabel = searchPromptStyle.Render("> Summary:")
+	const labelWidth = 20
+	row := func(r int, name, value string) []string {
+		label := "  " + name + ":"
+		if cv.row == r {
+			label = searchPromptStyle.Render("> " + name + ":")
+		}
+		valueLines := strings.Split(value, "\n")
+		out := []string{label + strings.Repeat(" ", max(1, labelWidth-lipgloss.Width(label))) + valueLines[0]}
+		for _, l := range valueLines[1:] {
+			out = append(out, strings.Repeat(" ", labelWidth)+l)
+		}
+		return out
 	}
-	summaryVal := cv.summary
-	if cv.field == fieldSummary {
-		summaryVal += "\u2588" // block cursor
+	selector := func(r int, value string, n, i int) string {
+		if cv.row != r {
+			return value
+		}
+		return selectedStyle.Render(" ‹ "+value+" › ") + helpDescStyle.Render(fmt.Sprintf("  %d/%d", i+1, n))
+	}
+	text := func(r int, value, hint string) string {
+		if cv.row == r {
+			return value + "█"
+		}
+		if value == "" {
+			return helpDescStyle.Render(hint)
+		}
+		return value
 	}
-	lines = append(lines, summaryLabel+"  "+summaryVal)
-	lines = append(lines, "")
 
-	// Type field (selector)
-	typeLabel := "  Type:"
-	if cv.field == fieldType {
-		typeLabel = searchPromptStyle.Render("> Type:")
+	var body []string
+	rowStart := make([]int, cv.rowCount())
+
+	tmplName := "(none)"
+	if t := cv.template(); t != nil {
+		tmplName = t.Name
 	}
-	var typeParts []string
-	for i, t := range issueTypes {
-		if i == cv.typeIdx {
-			typeParts = append(typeParts, selectedStyle.Render(" "+t+" "))
-		} else {
-			typeParts = append(typeParts, helpDescStyle.Render(" "+t+" "))
+	rowStart[rowTemplate] = len(body)
+	if len(cv.templates) == 0 {
+		body = append(body, row(rowTemplate, "Template", helpDescStyle.Render("(none in ~/.config/shinkansen/templates)"))...)
+	} else {
+		body = append(body, row(rowTemplate, "Template", selector(rowTemplate, tmplName, len(cv.templates)+1, cv.tmplIdx+1))...)
+	}
+
+	projectIdx := 0
+	for i, p := range cv.projects {
+		if p == cv.project {
+			projectIdx = i
 		}
 	}
-	lines = append(lines, typeLabel+"     "+strings.Join(typeParts, "  "))
-	lines = append(lines, "")
+	rowStart[rowProject] = len(body)
+	body = append(body, row(rowProject, "Project", selector(rowProject, cv.project, len(cv.projects), projectIdx))...)
 
-	// Priority field (selector)
-	prioLabel := "  Priority:"
-	if cv.field == fieldPriority {
-		prioLabel = searchPromptStyle.Render("> Priority:")
+	typeName := "..."
+	if cv.typeIdx < len(cv.types) {
+		typeName = cv.types[cv.typeIdx].Name
 	}
-	var prioParts []string
-	for i, p := range issuePriorities {
-		if i == cv.prioIdx {
-			prioParts = append(prioParts, selectedStyle.Render(" "+p+" "))
-		} else {
-			prioParts = append(prioParts, helpDescStyle.Render(" "+p+" "))
-		}
+	rowStart[rowType] = len(body)
+	body = append(body, row(rowType, "Type", selector(rowType, typeName, len(cv.types), cv.typeIdx))...)
+
+	rowStart[rowSummary] = len(body)
+	body = append(body, row(rowSummary, "Summary*", text(rowSummary, cv.summary, ""))...)
+
+	parentName := "Parent"
+	if cv.issueType().Subtask {
+		parentName = "Parent*"
 	}
-	lines = append(lines, prioLabel+" "+strings.Join(prioParts, "  "))
-	lines = append(lines, "")
+	rowStart[rowParent] = len(body)
+	body = append(body, row(rowParent, parentName, text(rowParent, cv.parent, "(epic or parent key)"))...)
 
-	// Description field (multiline text)
-	descLabel := "  Description:"
-	if cv.field == fieldDescription {
-		descLabel = searchPromptStyle.Render("> Description:")
+	for i, v := range cv.vars {
+		r := fixedRows + i
+		rowStart[r] = len(body)
+		body = append(body, row(r, truncate("{"+v.name+"}*", labelWidth-5), text(r, v.value, "(template placeholder)"))...)
 	}
-	lines = append(lines, descLabel)
-	descText := cv.desc
-	if cv.field == fieldDescription {
-		descText += "\u2588"
+
+	if cv.loading {
+		body = append(body, helpDescStyle.Render("  Loading create screen..."))
 	}
-	if descText == "" && cv.field != fieldDescription {
-		lines = append(lines, helpDescStyle.Render("    (optional)"))
-	} else {
-		for _, dl := range strings.Split(descText, "\n") {
-			lines = append(lines, "    "+dl)
+	for i := range cv.fields {
+		f := &cv.fields[i]
+		r := fixedRows + len(cv.vars) + i
+		name := f.meta.Name
+		if f.meta.Required && !f.meta.HasDefaultValue {
+			name += "*"
 		}
+		rowStart[r] = len(body)
+		body = append(body, row(r, truncate(name, labelWidth-5), f.display(cv.row == r))...)
+	}
+
+	// Keep the focused row in view on long create screens
+	avail := height - 12
+	if avail > 0 && len(body) > avail {
+		start := min(max(0, rowStart[cv.row]-avail/2), len(body)-avail)
+		body = body[start : start+avail]
+	}
+
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render("Create New Issue"))
+	lines = append(lines, body...)
+	if cv.metaErr != "" {
+		lines = append(lines, "")
+		lines = append(lines, helpDescStyle.Render("  "+cv.metaErr))
 	}
 
 	// Error message
@@ -278,12 +932,11 @@ func (cv CreateView) View(width, height int) string {
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, helpDescStyle.Render("  Tab/Shift+Tab: navigate fields  Left/Right: select option"))
+	lines = append(lines, helpDescStyle.Render("  Tab/↑↓: navigate fields  Left/Right: select option  *: required"))
 	lines = append(lines, helpDescStyle.Render("  Ctrl+S: create issue  Esc: cancel"))
 
 	content := strings.Join(lines, "\n")
 	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
-		panelStyle.Width(min(width-4, 80)).Render(content),
+		panelStyle.Width(min(width-4, 90)).Render(content),
 	)
 }
-
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/daemon"
+)
+
+// daemonMsg is a message pushed by the sync daemon.
+type daemonMsg daemon.Message
+
+// daemonGoneMsg is sent when the daemon connection closes.
+type daemonGoneMsg struct{}
+
+// connectDaemon subscribes to a running sync daemon, if there is one, so
+// the TUI stops polling Jira itself.
+func (a *App) connectDaemon() tea.Cmd {
+	client, err := daemon.Dial()
+	if err != nil {
+		return nil
+	}
+	msgs, err := daemon.Subscribe()
+	if err != nil {
+		client.Close()
+		return nil
+	}
+	a.daemon, a.daemonMsgs = client, msgs
+	return a.waitDaemon()
+}
+
+// waitDaemon waits for the daemon's next message.
+func (a *App) waitDaemon() tea.Cmd {
+	msgs := a.daemonMsgs
+	if msgs == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg, ok := <-msgs
+		if !ok {
+			return daemonGoneMsg{}
+		}
+		return daemonMsg(msg)
+	}
+}
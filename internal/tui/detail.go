@@ -2,36 +2,117 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/mattn/go-runewidth"
 	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
+// detailTab identifies which panel of the detail view is shown.
+type detailTab int
+
+const (
+	tabOverview detailTab = iota
+	tabHistory
+	tabWorklog
+	tabAttachments
+	tabSubtasks
+	tabCount // sentinel: total number of tabs
+)
+
+var detailTabNames = []string{"Overview", "History", "Worklog", "Attachments", "Subtasks"}
+
+// historyLoadedMsg is sent after an issue's changelog has been fetched.
+type historyLoadedMsg struct {
+	issueKey  string
+	histories []jira.ChangelogHistory
+	err       error
+}
+
+// detailsLoadedMsg is sent after an issue synced without its description
+// and comments has been fetched in full.
+type detailsLoadedMsg struct {
+	issue *jira.Issue
+	key   string
+	err   error
+}
+
 type DetailView struct {
 	issue       *jira.Issue
 	scrollY     int
+	tab         detailTab
 	commenting  bool
 	commentBuf  string
 	commentSent bool
-	logging     bool   // true when in time logging mode
-	logBuf      string // time input buffer (e.g. "2h", "30m")
+	logging     bool // true while the log work form is open
+	logForm     WorklogForm
 	logSent     bool
+
+	history        []jira.ChangelogHistory
+	historyKey     string // issue key the history was loaded for
+	historyLoading bool
+
+	detailsKey     string // issue key the full issue was checked for
+	detailsLoading bool
+
+	worklogs       []jira.Worklog
+	worklogKey     string // issue key the worklogs were loaded for
+	worklogLoading bool
+	worklogCursor  int
+	confirmDelete  bool // waiting for y/n before deleting the selected worklog
+
+	attachCursor  int
+	uploading     bool // true while the upload path prompt is open
+	uploadBuf     string
+	uploadMatches []string // completion candidates for uploadBuf
+
+	watchers    []jira.User
+	watchersKey string // issue key the watcher list was loaded for
+
+	customFields map[string]string // friendly name -> custom field ID
+
+	cloneForm CloneForm
+	splitForm SplitForm
+
+	subtaskCursor int
+	addingSubtask bool // true while the quick-add prompt is open
+	subtaskBuf    string
 }
 
-func NewDetailView() DetailView {
-	return DetailView{}
+func NewDetailView(customFields map[string]string) DetailView {
+	return DetailView{customFields: customFields}
 }
 
 func (dv *DetailView) SetIssue(issue *jira.Issue) {
 	dv.issue = issue
 	dv.scrollY = 0
+	dv.tab = tabOverview
+	dv.history = nil
+	dv.historyKey = ""
+	dv.historyLoading = false
+	dv.detailsKey = ""
+	dv.detailsLoading = false
 	dv.commenting = false
 	dv.commentBuf = ""
 	dv.logging = false
-	dv.logBuf = ""
+	dv.logForm.Hide()
+	dv.worklogs = nil
+	dv.worklogKey = ""
+	dv.worklogLoading = false
+	dv.worklogCursor = 0
+	dv.confirmDelete = false
+	dv.attachCursor = 0
+	dv.uploading = false
+	dv.subtaskCursor = 0
+	dv.addingSubtask = false
+	dv.uploadBuf = ""
+	dv.uploadMatches = nil
+	dv.watchers = nil
+	dv.watchersKey = ""
 }
 
 func (dv *DetailView) StartComment() {
@@ -40,8 +121,69 @@ func (dv *DetailView) StartComment() {
 }
 
 func (dv *DetailView) StartLogTime() {
+	if dv.issue == nil {
+		return
+	}
 	dv.logging = true
-	dv.logBuf = ""
+	dv.logForm.Show(dv.issue.Key)
+}
+
+// switchTab moves to another tab, loading its data on first visit.
+func (dv *DetailView) switchTab(tab detailTab, app *App) tea.Cmd {
+	dv.tab = tab
+	dv.scrollY = 0
+	if dv.issue == nil {
+		return nil
+	}
+	if tab == tabHistory && dv.historyKey != dv.issue.Key {
+		return dv.loadHistory(app)
+	}
+	if tab == tabWorklog && dv.worklogKey != dv.issue.Key {
+		return dv.loadWorklogs(app)
+	}
+	return nil
+}
+
+// loadHistory shows the cached changelog immediately and refreshes it from Jira.
+func (dv *DetailView) loadHistory(app *App) tea.Cmd {
+	key := dv.issue.Key
+	if cached, err := app.store.GetHistory(key); err == nil {
+		dv.history = cached
+	}
+	dv.historyKey = key
+	dv.historyLoading = true
+	return func() tea.Msg {
+		histories, err := app.client.GetChangelog(key)
+		if err != nil {
+			return historyLoadedMsg{issueKey: key, err: err}
+		}
+		if err := app.store.UpsertHistory(key, histories); err != nil {
+			return historyLoadedMsg{issueKey: key, err: fmt.Errorf("saving to the cache: %w", err)}
+		}
+		cached, err := app.store.GetHistory(key)
+		return historyLoadedMsg{issueKey: key, histories: cached, err: err}
+	}
+}
+
+// loadDetails fetches the description, comments, attachments and changelog
+// of an issue the sync only cached list fields for.
+func (dv *DetailView) loadDetails(app *App) tea.Cmd {
+	key := dv.issue.Key
+	dv.detailsKey = key
+	if dv.issue.HasDetails() {
+		return nil
+	}
+	dv.detailsLoading = true
+	return func() tea.Msg {
+		issue, err := app.client.GetIssue(key)
+		if err != nil {
+			return detailsLoadedMsg{key: key, err: err}
+		}
+		if err := app.store.UpsertIssue(issue); err != nil {
+			return detailsLoadedMsg{key: key, issue: issue, err: fmt.Errorf("saving to the cache: %w", err)}
+		}
+		return detailsLoadedMsg{key: key, issue: issue}
+	}
 }
 
 func (dv DetailView) Update(msg tea.Msg, app *App) (DetailView, tea.Cmd) {
@@ -57,13 +199,14 @@ func (dv DetailView) Update(msg tea.Msg, app *App) (DetailView, tea.Cmd) {
 					dv.commenting = false
 					dv.commentBuf = ""
 					dv.commentSent = true
+					resync := app.syncCmd()
 					return dv, func() tea.Msg {
 						app.client.AddComment(key, comment)
 						issue, err := app.client.GetIssue(key)
 						if err == nil {
 							app.store.UpsertIssue(issue)
 						}
-						return syncDoneMsg{result: cache.Sync(app.client, app.store, app.cfg.DefaultProject)}
+						return resync()
 					}
 				}
 				dv.commenting = false
@@ -82,37 +225,197 @@ func (dv DetailView) Update(msg tea.Msg, app *App) (DetailView, tea.Cmd) {
 			return dv, nil
 		}
 
-		// Time logging input mode
+		// Log work form
 		if dv.logging {
+			var cmd tea.Cmd
+			dv.logForm, cmd = dv.logForm.Update(msg, app)
+			if !dv.logForm.visible {
+				dv.logging = false
+				dv.logSent = cmd != nil
+			}
+			return dv, cmd
+		}
+
+		// Clone and split forms
+		if dv.cloneForm.visible {
+			var cmd tea.Cmd
+			dv.cloneForm, cmd = dv.cloneForm.Update(msg, app)
+			return dv, cmd
+		}
+		if dv.splitForm.visible {
+			var cmd tea.Cmd
+			dv.splitForm, cmd = dv.splitForm.Update(msg, app)
+			return dv, cmd
+		}
+
+		// Upload path prompt
+		if dv.uploading {
 			switch msg.String() {
 			case "enter":
-				if dv.logBuf != "" && dv.issue != nil {
-					timeSpent := dv.logBuf
-					key := dv.issue.Key
-					dv.logging = false
-					dv.logBuf = ""
-					dv.logSent = true
-					return dv, func() tea.Msg {
-						app.client.LogWork(key, timeSpent)
-						return logWorkDoneMsg{issueKey: key}
-					}
+				dv.uploading = false
+				dv.uploadMatches = nil
+				if dv.uploadBuf != "" && dv.issue != nil {
+					return dv, dv.uploadAttachment(dv.uploadBuf, app)
 				}
-				dv.logging = false
 			case "esc":
-				dv.logging = false
-				dv.logBuf = ""
+				dv.uploading = false
+				dv.uploadMatches = nil
+			case "tab":
+				dv.uploadBuf, dv.uploadMatches = completePath(dv.uploadBuf)
+			case "backspace":
+				if len(dv.uploadBuf) > 0 {
+					dv.uploadBuf = dv.uploadBuf[:len(dv.uploadBuf)-1]
+				}
+				dv.uploadMatches = nil
+			default:
+				if len(msg.String()) == 1 || msg.String() == " " {
+					dv.uploadBuf += msg.String()
+				}
+				dv.uploadMatches = nil
+			}
+			return dv, nil
+		}
+
+		// Subtask quick-add prompt
+		if dv.addingSubtask {
+			switch msg.String() {
+			case "enter":
+				if summary := strings.TrimSpace(dv.subtaskBuf); summary != "" && dv.issue != nil {
+					dv.subtaskBuf = ""
+					app.flashMsg = "Creating subtask..."
+					return dv, dv.addSubtask(summary, app)
+				}
+			case "esc":
+				dv.addingSubtask = false
+				dv.subtaskBuf = ""
 			case "backspace":
-				if len(dv.logBuf) > 0 {
-					dv.logBuf = dv.logBuf[:len(dv.logBuf)-1]
+				if len(dv.subtaskBuf) > 0 {
+					dv.subtaskBuf = dv.subtaskBuf[:len(dv.subtaskBuf)-1]
 				}
 			default:
 				if len(msg.String()) == 1 || msg.String() == " " {
-					dv.logBuf += msg.String()
+					dv.subtaskBuf += msg.String()
 				}
 			}
 			return dv, nil
 		}
 
+		// Worklog delete confirmation
+		if dv.confirmDelete {
+			dv.confirmDelete = false
+			if w := dv.selectedWorklog(); w != nil && msg.String() == "y" {
+				key := dv.issue.Key
+				id := w.ID
+				return dv, func() tea.Msg {
+					err := app.client.DeleteWorklog(key, id)
+					return logWorkDoneMsg{issueKey: key, deleted: true, err: err}
+				}
+			}
+			return dv, nil
+		}
+
+		// Worklog tab keys
+		if dv.tab == tabWorklog {
+			switch msg.String() {
+			case "down":
+				if dv.worklogCursor < len(dv.worklogs)-1 {
+					dv.worklogCursor++
+				}
+				return dv, nil
+			case "up":
+				if dv.worklogCursor > 0 {
+					dv.worklogCursor--
+				}
+				return dv, nil
+			case "e", "x":
+				w := dv.selectedWorklog()
+				if w == nil {
+					return dv, nil
+				}
+				if w.Author.AccountID != app.cfg.AccountID {
+					app.flashMsg = "You can only change your own worklogs"
+					return dv, nil
+				}
+				if app.blocked("log", dv.issue.Key) {
+					return dv, nil
+				}
+				if msg.String() == "e" {
+					dv.logging = true
+					dv.logForm.ShowEdit(dv.issue.Key, *w)
+				} else {
+					dv.confirmDelete = true
+				}
+				return dv, nil
+			}
+		}
+
+		// Attachments tab keys
+		if dv.tab == tabAttachments && dv.issue != nil {
+			switch msg.String() {
+			case "down":
+				if dv.attachCursor < len(dv.issue.Fields.Attachments)-1 {
+					dv.attachCursor++
+				}
+				return dv, nil
+			case "up":
+				if dv.attachCursor > 0 {
+					dv.attachCursor--
+				}
+				return dv, nil
+			case "enter":
+				return dv, dv.attachmentAction("open", app)
+			case "d":
+				return dv, dv.attachmentAction("download", app)
+			case "i":
+				return dv, dv.previewAttachment(app)
+			case "u":
+				if app.blocked("attach", dv.issue.Key) {
+					return dv, nil
+				}
+				dv.uploading = true
+				dv.uploadBuf = ""
+				dv.uploadMatches = nil
+				return dv, nil
+			}
+		}
+
+		// Subtasks tab keys
+		if dv.tab == tabSubtasks && dv.issue != nil {
+			switch msg.String() {
+			case "down":
+				if dv.subtaskCursor < len(dv.issue.Fields.Subtasks)-1 {
+					dv.subtaskCursor++
+				}
+				return dv, nil
+			case "up":
+				if dv.subtaskCursor > 0 {
+					dv.subtaskCursor--
+				}
+				return dv, nil
+			case "n":
+				if app.blocked("create", dv.issue.Key) {
+					return dv, nil
+				}
+				dv.addingSubtask = true
+				dv.subtaskBuf = ""
+				return dv, nil
+			case "x", " ":
+				if app.blocked("transition", dv.issue.Key) {
+					return dv, nil
+				}
+				return dv, dv.toggleSubtask(app)
+			case "enter":
+				if st := dv.selectedSubtask(); st != nil {
+					sub := *st
+					if cached, err := app.store.GetIssue(st.Key); err == nil {
+						sub = *cached
+					}
+					dv.SetIssue(&sub)
+				}
+				return dv, nil
+			}
+		}
+
 		// Normal detail view keys
 		switch msg.String() {
 		case "q", "esc":
@@ -123,31 +426,144 @@ func (dv DetailView) Update(msg tea.Msg, app *App) (DetailView, tea.Cmd) {
 			if dv.scrollY > 0 {
 				dv.scrollY--
 			}
+		case "tab":
+			return dv, dv.switchTab((dv.tab+1)%tabCount, app)
+		case "shift+tab":
+			return dv, dv.switchTab((dv.tab-1+tabCount)%tabCount, app)
 		case "c":
-			dv.StartComment()
+			if dv.issue != nil && !app.blocked("comment", dv.issue.Key) {
+				dv.StartComment()
+			}
 		case "t":
-			dv.StartLogTime()
+			if dv.issue != nil && !app.blocked("log", dv.issue.Key) {
+				dv.StartLogTime()
+			}
+		case "s":
+			if dv.issue != nil {
+				// Stopping the timer opens the log work form on app.detail
+				cmd := app.toggleTimer(dv.issue.Key)
+				return app.detail, cmd
+			}
 		case "m":
 			if dv.issue != nil {
 				return dv, app.showTransitions(dv.issue.Key)
 			}
+		case "w":
+			if dv.issue != nil {
+				return dv, dv.toggleWatch(app)
+			}
+		case "v":
+			if dv.issue != nil {
+				return dv, dv.toggleVote(app)
+			}
+		case "X", "M":
+			if dv.issue != nil {
+				action := map[string]string{"X": "delete", "M": "move"}[msg.String()]
+				return dv, app.startManage(action, dv.issue)
+			}
+		case "Y":
+			if dv.issue != nil && !app.blocked("create", dv.issue.Key) {
+				dv.cloneForm.Show(dv.issue)
+			}
+		case "S":
+			if dv.issue != nil && !app.blocked("create", dv.issue.Key) && !dv.splitForm.Show(dv.issue) {
+				app.flashMsg = "No checklist items in the description"
+			}
+		case "l", "C", "V":
+			if dv.issue != nil && !app.blocked("edit", dv.issue.Key) {
+				kind := map[string]fieldKind{"l": kindLabels, "C": kindComponents, "V": kindFixVersions}[msg.String()]
+				f := &dv.issue.Fields
+				current := [][]string{f.Labels, f.ComponentNames(), f.FixVersionNames()}[kind]
+				return dv, app.fieldPicker.Show(kind, dv.issue.Key, f.Project.Key, current, app)
+			}
 		}
 	}
 	return dv, nil
 }
 
-func (dv DetailView) View(width, height int) string {
+// View renders the detail view; hints is the rendered footer, with actions
+// the user has no permission for greyed out.
+func (dv DetailView) View(width, height int, accountID, hints string) string {
 	if dv.issue == nil {
 		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
 			helpDescStyle.Render("No issue selected"))
 	}
+	if dv.logging {
+		return dv.logForm.View(width, height)
+	}
+	if dv.cloneForm.visible {
+		return dv.cloneForm.View(width, height)
+	}
+	if dv.splitForm.visible {
+		return dv.splitForm.View(width, height)
+	}
 
-	i := dv.issue
 	var lines []string
-
-	lines = append(lines, detailHeaderStyle.Render(fmt.Sprintf("%s: %s", i.Key, i.Fields.Summary)))
+	lines = append(lines, detailHeaderStyle.Render(fmt.Sprintf("%s: %s", dv.issue.Key, dv.issue.Fields.Summary)))
+	lines = append(lines, dv.renderTabs())
 	lines = append(lines, "")
 
+	switch dv.tab {
+	case tabHistory:
+		lines = append(lines, dv.renderHistory(width)...)
+	case tabWorklog:
+		lines = append(lines, dv.renderWorklogs(width, accountID)...)
+	case tabAttachments:
+		lines = append(lines, dv.renderAttachments(width)...)
+	case tabSubtasks:
+		lines = append(lines, dv.renderSubtasks(width)...)
+	default:
+		lines = append(lines, dv.renderOverview(width)...)
+	}
+
+	// Comment input or sent indicator
+	if dv.commenting {
+		lines = append(lines, "")
+		lines = append(lines, searchPromptStyle.Render("Add comment: ")+dv.commentBuf+"█")
+	} else if dv.commentSent {
+		lines = append(lines, "")
+		lines = append(lines, helpDescStyle.Render("Posting comment..."))
+	}
+
+	// Time logging sent indicator
+	if dv.logSent {
+		lines = append(lines, "")
+		lines = append(lines, helpDescStyle.Render("Logging work..."))
+	}
+
+	// Apply scroll
+	if dv.scrollY > 0 && dv.scrollY < len(lines) {
+		lines = lines[dv.scrollY:]
+	}
+	if len(lines) > height-2 {
+		lines = lines[:height-2]
+	}
+
+	content := strings.Join(lines, "\n")
+
+	footer := statusBarStyle.Render(hints)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		panelStyle.Width(width-2).Render(content),
+		footer,
+	)
+}
+
+func (dv DetailView) renderTabs() string {
+	var tabs []string
+	for t := detailTab(0); t < tabCount; t++ {
+		if t == dv.tab {
+			tabs = append(tabs, activeTabStyle.Render(detailTabNames[t]))
+		} else {
+			tabs = append(tabs, tabStyle.Render(detailTabNames[t]))
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (dv DetailView) renderOverview(width int) []string {
+	i := dv.issue
+	var lines []string
+
 	lines = append(lines, detailLabelStyle.Render("Status:")+" "+detailValueStyle.Render(i.Fields.Status.Name))
 	lines = append(lines, detailLabelStyle.Render("Priority:")+" "+detailValueStyle.Render(i.Fields.Priority.Name))
 	lines = append(lines, detailLabelStyle.Render("Type:")+" "+detailValueStyle.Render(i.Fields.IssueType.Name))
@@ -158,6 +574,24 @@ func (dv DetailView) View(width, height int) string {
 	}
 	lines = append(lines, detailLabelStyle.Render("Project:")+" "+detailValueStyle.Render(i.Fields.Project.Name))
 	lines = append(lines, detailLabelStyle.Render("Updated:")+" "+detailValueStyle.Render(i.Fields.Updated))
+	chipRows := []struct {
+		label  string
+		values []string
+	}{
+		{"Labels:", i.Fields.Labels},
+		{"Components:", i.Fields.ComponentNames()},
+		{"Fixed in:", i.Fields.FixVersionNames()},
+	}
+	for _, r := range chipRows {
+		if len(r.values) > 0 {
+			lines = append(lines, detailLabelStyle.Render(r.label)+" "+renderChips(r.values, width-20))
+		}
+	}
+	if progress := subtaskProgress(i, 20); progress != "" {
+		lines = append(lines, detailLabelStyle.Render("Subtasks:")+" "+progress)
+	}
+	lines = append(lines, dv.renderCustomFields(width)...)
+	lines = append(lines, dv.renderWatchers()...)
 
 	if i.Fields.TimeTracking != nil {
 		var timeParts []string
@@ -177,6 +611,9 @@ func (dv DetailView) View(width, height int) string {
 	lines = append(lines, "")
 
 	desc := i.DescriptionText()
+	if dv.detailsLoading {
+		lines = append(lines, helpDescStyle.Render("Loading description and comments..."), "")
+	}
 	if desc != "" {
 		lines = append(lines, detailLabelStyle.Render("Description:"))
 		descLines := strings.Split(desc, "\n")
@@ -206,37 +643,104 @@ func (dv DetailView) View(width, height int) string {
 		}
 	}
 
-	// Comment input or sent indicator
-	if dv.commenting {
-		lines = append(lines, "")
-		lines = append(lines, searchPromptStyle.Render("Add comment: ")+dv.commentBuf+"█")
-	} else if dv.commentSent {
-		lines = append(lines, "")
-		lines = append(lines, helpDescStyle.Render("Posting comment..."))
-	}
+	return lines
+}
 
-	// Time logging input or sent indicator
-	if dv.logging {
-		lines = append(lines, "")
-		lines = append(lines, searchPromptStyle.Render("Log time (e.g. 2h, 30m): ")+dv.logBuf+"█")
-	} else if dv.logSent {
+// historyGroupWindow merges consecutive changes by the same author that were
+// made within a few minutes of each other (e.g. an edit followed by a move).
+const historyGroupWindow = 5 * time.Minute
+
+func (dv DetailView) renderHistory(width int) []string {
+	var lines []string
+	if len(dv.history) == 0 {
+		if dv.historyLoading {
+			return append(lines, helpDescStyle.Render("Loading history..."))
+		}
+		return append(lines, helpDescStyle.Render("No history"))
+	}
+	if dv.historyLoading {
+		lines = append(lines, helpDescStyle.Render("Refreshing history..."))
 		lines = append(lines, "")
-		lines = append(lines, helpDescStyle.Render("Time logged"))
 	}
 
-	// Apply scroll
-	if dv.scrollY > 0 && dv.scrollY < len(lines) {
-		lines = lines[dv.scrollY:]
+	var groupAuthor string
+	var groupStart time.Time
+	for gi, h := range dv.history {
+		created := h.CreatedTime()
+		if gi == 0 || h.Author.AccountID != groupAuthor || groupStart.Sub(created) > historyGroupWindow {
+			if gi > 0 {
+				lines = append(lines, "")
+			}
+			author := h.Author.DisplayName
+			if author == "" {
+				author = "Jira"
+			}
+			lines = append(lines, fmt.Sprintf("%s — %s", helpKeyStyle.Render(author), helpDescStyle.Render(created.Local().Format("2006-01-02 15:04"))))
+			groupAuthor = h.Author.AccountID
+			groupStart = created
+		}
+
+		for _, item := range h.Items {
+			label := detailLabelStyle.Render(item.Field + ":")
+			if item.Field == "description" {
+				lines = append(lines, "  "+label)
+				for _, d := range lineDiff(item.FromString, item.ToString) {
+					text := truncate(d.text, width-10)
+					switch d.op {
+					case diffDelete:
+						lines = append(lines, "    "+diffDelStyle.Render("- "+text))
+					case diffInsert:
+						lines = append(lines, "    "+diffAddStyle.Render("+ "+text))
+					default:
+						lines = append(lines, "    "+helpDescStyle.Render("  "+text))
+					}
+				}
+				continue
+			}
+			from, to := item.FromString, item.ToString
+			if from == "" {
+				from = "(none)"
+			}
+			if to == "" {
+				to = "(none)"
+			}
+			lines = append(lines, "  "+label+" "+detailValueStyle.Render(truncate(from+" → "+to, width-18)))
+		}
 	}
-	if len(lines) > height-2 {
-		lines = lines[:height-2]
+	return lines
+}
+
+// truncate shortens s to at most n terminal cells, marking the cut with
+// "...". It cuts between runes, so names and summaries in any script stay
+// intact.
+func truncate(s string, n int) string {
+	if n < 4 {
+		return s
 	}
+	return runewidth.Truncate(s, n, "...")
+}
 
-	content := strings.Join(lines, "\n")
+// renderCustomFields shows the mapped custom fields that have a value, in
+// name order. Long names are cut to fit the label column.
+func (dv DetailView) renderCustomFields(width int) []string {
+	names := make([]string, 0, len(dv.customFields))
+	for name := range dv.customFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	footer := statusBarStyle.Render("esc:back  o:browser  a:assign  c:comment  t:log  m:move  ?:help")
-	return lipgloss.JoinVertical(lipgloss.Left,
-		panelStyle.Width(width-2).Render(content),
-		footer,
-	)
+	var lines []string
+	for _, name := range names {
+		text := dv.issue.Fields.CustomText(dv.customFields[name])
+		if text == "" {
+			continue
+		}
+		label := strings.ReplaceAll(name, "_", " ")
+		label = runewidth.Truncate(label, 10, "")
+		if max := width - 20; max > 10 {
+			text = truncate(text, max)
+		}
+		lines = append(lines, detailLabelStyle.Render(label+":")+" "+detailValueStyle.Render(text))
+	}
+	return lines
 }
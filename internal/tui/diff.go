@@ -0,0 +1,69 @@
+package tui
+
+import "strings"
+
+// diffOp marks a line as unchanged, removed or added.
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// lineDiff computes a line-based diff between two texts using the longest
+// common subsequence. Descriptions are short enough that O(n*m) is fine.
+func lineDiff(from, to string) []diffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+	if from == "" {
+		a = nil
+	}
+	if to == "" {
+		b = nil
+	}
+
+	// lcs[i][j] = length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{diffDelete, a[i]})
+			i++
+		default:
+			out = append(out, diffLine{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{diffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{diffInsert, b[j]})
+	}
+	return out
+}
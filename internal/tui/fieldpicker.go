@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// fieldKind identifies a multi-value field edited with the FieldPicker.
+type fieldKind int
+
+const (
+	kindLabels fieldKind = iota
+	kindComponents
+	kindFixVersions
+	kindOptions // a fixed list of values, e.g. a custom field's allowed values
+)
+
+var fieldKindNames = []string{"Labels", "Components", "Fix versions", "Values"}
+
+// fieldKindCache maps a field kind to its name in cache.Store.FieldValues.
+var fieldKindCache = []string{"label", "component", "version", ""}
+
+// fieldOptionsMsg carries the values a field can take.
+type fieldOptionsMsg struct {
+	kind    fieldKind
+	options []string
+	err     error
+}
+
+// fieldsUpdatedMsg is sent after an issue's fields were changed in Jira.
+type fieldsUpdatedMsg struct {
+	issueKey string
+	err      error
+}
+
+// FieldPicker is a multi-select list for labels, components and fix versions.
+// With formField set the choice goes back to that create form field instead
+// of being saved on an issue.
+type FieldPicker struct {
+	visible   bool
+	kind      fieldKind
+	issueKey  string
+	formField string
+	title     string
+	options   []string
+	selected  map[string]bool
+	query     string // filters options; for labels it can also be a new label
+	cursor    int
+	loading   bool
+	errMsg    string
+}
+
+// Show opens the picker with the current values selected and loads the
+// project's options.
+func (fp *FieldPicker) Show(kind fieldKind, issueKey, projectKey string, current []string, app *App) tea.Cmd {
+	fp.visible = true
+	fp.kind = kind
+	fp.issueKey = issueKey
+	fp.formField = ""
+	fp.title = fieldKindNames[kind]
+	fp.options = append([]string{}, current...)
+	fp.selected = make(map[string]bool)
+	for _, v := range current {
+		fp.selected[v] = true
+	}
+	fp.query = ""
+	fp.cursor = 0
+	fp.loading = true
+	fp.errMsg = ""
+	if kind == kindOptions {
+		return nil // the caller supplies the options
+	}
+
+	return func() tea.Msg {
+		var names []string
+		var err error
+		switch kind {
+		case kindLabels:
+			names, err = app.client.GetLabels()
+		case kindComponents:
+			var components []jira.Component
+			components, err = app.client.GetComponents(projectKey)
+			for _, c := range components {
+				names = append(names, c.Name)
+			}
+		case kindFixVersions:
+			var versions []jira.Version
+			versions, err = app.client.GetVersions(projectKey)
+			for _, v := range versions {
+				if !v.Archived {
+					names = append(names, v.Name)
+				}
+			}
+		}
+		// Values seen on cached issues fill in for offline use
+		cached, _ := app.store.FieldValues(fieldKindCache[kind])
+		return fieldOptionsMsg{kind: kind, options: append(names, cached...), err: err}
+	}
+}
+
+// ShowForForm opens the picker for a create form field. Labels are loaded
+// like on an issue; other kinds use the given options.
+func (fp *FieldPicker) ShowForForm(kind fieldKind, fieldID, title, projectKey string, options, current []string, app *App) tea.Cmd {
+	cmd := fp.Show(kind, "", projectKey, current, app)
+	if kind == kindOptions {
+		fp.setOptions(options)
+	}
+	fp.formField = fieldID
+	fp.title = title
+	return cmd
+}
+
+func (fp *FieldPicker) Hide() {
+	fp.visible = false
+}
+
+// setOptions merges loaded options with the ones already listed.
+func (fp *FieldPicker) setOptions(options []string) {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, o := range append(fp.options, options...) {
+		if o != "" && !seen[o] {
+			seen[o] = true
+			merged = append(merged, o)
+		}
+	}
+	sort.Strings(merged)
+	fp.options = merged
+	fp.loading = false
+}
+
+// filtered returns the options matching the query. For labels, a query that
+// isn't an existing label is offered first so new labels can be created.
+func (fp *FieldPicker) filtered() []string {
+	q := strings.ToLower(fp.query)
+	var out []string
+	exact := false
+	for _, o := range fp.options {
+		if strings.Contains(strings.ToLower(o), q) {
+			out = append(out, o)
+		}
+		if o == fp.query {
+			exact = true
+		}
+	}
+	if fp.kind == kindLabels && fp.query != "" && !exact {
+		out = append([]string{fp.query}, out...)
+	}
+	return out
+}
+
+// values returns the selected values in display order.
+func (fp *FieldPicker) values() []string {
+	values := []string{}
+	for _, o := range fp.options {
+		if fp.selected[o] {
+			values = append(values, o)
+		}
+	}
+	return values
+}
+
+// fieldUpdate builds the Jira fields payload for a kind's values.
+func fieldUpdate(kind fieldKind, values []string) map[string]interface{} {
+	switch kind {
+	case kindComponents:
+		return map[string]interface{}{"components": jira.NameRefs(values)}
+	case kindFixVersions:
+		return map[string]interface{}{"fixVersions": jira.NameRefs(values)}
+	default:
+		return map[string]interface{}{"labels": values}
+	}
+}
+
+func (fp FieldPicker) Update(msg tea.Msg, app *App) (FieldPicker, tea.Cmd) {
+	if !fp.visible {
+		return fp, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		options := fp.filtered()
+		switch msg.String() {
+		case "esc":
+			fp.Hide()
+		case "down":
+			if fp.cursor < len(options)-1 {
+				fp.cursor++
+			}
+		case "up":
+			if fp.cursor > 0 {
+				fp.cursor--
+			}
+		case " ", "tab":
+			if fp.cursor < len(options) {
+				o := options[fp.cursor]
+				if !fp.selected[o] {
+					fp.setOptions([]string{o}) // keep a newly typed label
+				}
+				fp.selected[o] = !fp.selected[o]
+				fp.query = ""
+				fp.cursor = 0
+			}
+		case "enter":
+			fp.Hide()
+			values := fp.values()
+			if fp.formField != "" {
+				app.create.setValues(fp.formField, values)
+				return fp, nil
+			}
+			key := fp.issueKey
+			update := fieldUpdate(fp.kind, values)
+			app.flashMsg = fmt.Sprintf("Updating %s...", key)
+			return fp, func() tea.Msg {
+				if err := app.client.UpdateIssueFields(key, update); err != nil {
+					return fieldsUpdatedMsg{issueKey: key, err: err}
+				}
+				return fieldsUpdatedMsg{issueKey: key, err: refreshIssue(app, key)}
+			}
+		case "backspace":
+			if len(fp.query) > 0 {
+				fp.query = fp.query[:len(fp.query)-1]
+				fp.cursor = 0
+			}
+		default:
+			if len(msg.String()) == 1 {
+				fp.query += msg.String()
+				fp.cursor = 0
+			}
+		}
+	}
+	return fp, nil
+}
+
+func (fp FieldPicker) View(width, height int) string {
+	if !fp.visible {
+		return ""
+	}
+
+	title := fp.title
+	if fp.issueKey != "" {
+		title += " for " + fp.issueKey
+	}
+	var lines []string
+	lines = append(lines, searchPromptStyle.Render(title))
+	lines = append(lines, "")
+	lines = append(lines, searchPromptStyle.Render("Filter: ")+fp.query+"█")
+	lines = append(lines, "")
+
+	options := fp.filtered()
+	maxRows := height - 12
+	start := 0
+	if fp.cursor >= maxRows {
+		start = fp.cursor - maxRows + 1
+	}
+	for i := start; i < len(options) && i < start+maxRows; i++ {
+		o := options[i]
+		check := "[ ] "
+		if fp.selected[o] {
+			check = "[x] "
+		}
+		label := chip(o)
+		if i == fp.cursor {
+			label = selectedStyle.Render(" " + o + " ")
+		}
+		line := check + label
+		if fp.kind == kindLabels && o == fp.query && !contains(fp.options, o) {
+			line += helpDescStyle.Render("  (new label)")
+		}
+		lines = append(lines, "  "+line)
+	}
+	if len(options) == 0 {
+		lines = append(lines, helpDescStyle.Render("  No matching values"))
+	}
+	if fp.loading {
+		lines = append(lines, "")
+		lines = append(lines, helpDescStyle.Render("  Loading values..."))
+	}
+	if fp.errMsg != "" {
+		lines = append(lines, "")
+		lines = append(lines, diffDelStyle.Render("  "+fp.errMsg))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("Space: toggle  Enter: save  Esc: cancel"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 60)).Render(strings.Join(lines, "\n")),
+	)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
 // FilterView handles the JQL filter input with history support.
@@ -74,17 +75,21 @@ func (fv FilterView) Update(msg tea.Msg, app *App) (FilterView, tea.Cmd) {
 			}
 
 			// Save to history
+			var saveErr error
 			if fv.store != nil {
-				fv.store.SaveJQLFilter(jql)
+				saveErr = fv.store.SaveJQLFilter(jql)
 			}
 
 			fv.Hide()
 			app.currentView = viewIssues
 			app.flashMsg = "Filtering..."
+			if saveErr != nil {
+				app.flashMsg = fmt.Sprintf("Filtering... (not saved to history: %v)", saveErr)
+			}
 
 			query := jql
 			return fv, func() tea.Msg {
-				issues, err := app.client.SearchAll(query)
+				issues, err := app.client.SearchAll(query, jira.FieldsList)
 				if err != nil {
 					return statusMsg(fmt.Sprintf("Filter failed: %v", err))
 				}
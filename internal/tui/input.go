@@ -5,7 +5,6 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/temujinlabs/shinkansen/internal/cache"
 	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
@@ -58,9 +57,15 @@ func (tp TransitionPicker) Update(msg tea.Msg, app *App) (TransitionPicker, tea.
 
 				// Single issue transition
 				key := tp.issueKey
+				resync := app.syncCmd()
 				return tp, func() tea.Msg {
-					app.client.TransitionIssue(key, t.ID)
-					return syncDoneMsg{result: cache.Sync(app.client, app.store, app.cfg.DefaultProject)}
+					if err := app.client.TransitionIssue(key, t.ID); err != nil {
+						return issueActionMsg{issueKey: key, err: err}
+					}
+					if err := app.store.InvalidateTransitions(key); err != nil {
+						return issueActionMsg{issueKey: key, err: err}
+					}
+					return resync()
 				}
 			}
 		}
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/cache"
 	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
@@ -14,10 +15,37 @@ type IssueList struct {
 	cursor     int
 	offset     int
 	maxVisible int
+	watching   bool     // show only issues the user watches
+	columns    []string // custom field IDs shown as extra columns
 }
 
-func NewIssueList() IssueList {
-	return IssueList{}
+// NewIssueList builds the list with extra columns for the mapped custom
+// fields named in columns; unknown names are skipped.
+func NewIssueList(columns []string, customFields map[string]string) IssueList {
+	var il IssueList
+	for _, name := range columns {
+		if id, ok := customFields[name]; ok {
+			il.columns = append(il.columns, id)
+		}
+	}
+	return il
+}
+
+// columnWidth is the width of each custom field column.
+const columnWidth = 12
+
+// customColumns renders the configured custom field values for an issue as
+// fixed-width cells.
+func (il IssueList) customColumns(issue *jira.Issue) string {
+	var cells []string
+	for _, id := range il.columns {
+		text := []rune(issue.Fields.CustomText(id))
+		if len(text) > columnWidth {
+			text = append(text[:columnWidth-1], '…')
+		}
+		cells = append(cells, fmt.Sprintf("%-*s", columnWidth, string(text)))
+	}
+	return strings.Join(cells, " ")
 }
 
 func (il *IssueList) SetIssues(issues []jira.Issue) {
@@ -68,17 +96,30 @@ func (il IssueList) Update(msg tea.Msg, app *App) (IssueList, tea.Cmd) {
 				return il, app.showTransitions(issue.Key)
 			}
 		case "c":
-			if issue := il.SelectedIssue(); issue != nil {
+			if issue := il.SelectedIssue(); issue != nil && !app.blocked("comment", issue.Key) {
 				app.currentView = viewDetail
 				app.detail.SetIssue(issue)
 				app.detail.StartComment()
 			}
 		case "t":
-			if issue := il.SelectedIssue(); issue != nil {
+			if issue := il.SelectedIssue(); issue != nil && !app.blocked("log", issue.Key) {
 				app.currentView = viewDetail
 				app.detail.SetIssue(issue)
 				app.detail.StartLogTime()
 			}
+		case "s":
+			if issue := il.SelectedIssue(); issue != nil {
+				return il, app.toggleTimer(issue.Key)
+			}
+		case "X", "M":
+			action := map[string]string{"X": "delete", "M": "move"}[msg.String()]
+			return il, app.startManage(action, il.SelectedIssue())
+		case "w":
+			il.watching = !il.watching
+			il.cursor, il.offset = 0, 0
+			app.issues = il
+			app.loadFromCache()
+			return app.issues, nil
 		}
 	}
 	return il, nil
@@ -87,7 +128,11 @@ func (il IssueList) Update(msg tea.Msg, app *App) (IssueList, tea.Cmd) {
 func (il IssueList) View(width, height int, active bool, selections map[string]bool) string {
 	il.maxVisible = height - 4
 
-	title := panelTitleStyle.Render(fmt.Sprintf("My Issues (%d)", len(il.issues)))
+	name := "My Issues"
+	if il.watching {
+		name = "Watching"
+	}
+	title := panelTitleStyle.Render(fmt.Sprintf("%s (%d)", name, len(il.issues)))
 
 	var rows []string
 	end := min(il.offset+il.maxVisible, len(il.issues))
@@ -103,6 +148,20 @@ func (il IssueList) View(width, height int, active bool, selections map[string]b
 		key := issueKeyStyle.Render(issue.Key)
 		summary := issue.Fields.Summary
 		maxSumLen := width - 34
+		cols := ""
+		if len(il.columns) > 0 {
+			cols = il.customColumns(&issue)
+			maxSumLen -= len(il.columns) * (columnWidth + 1)
+		}
+		progress := subtaskProgress(&issue, 0)
+		if progress != "" {
+			maxSumLen -= lipgloss.Width(progress) + 1
+		}
+		chips := ""
+		if names := issueChips(&issue); len(names) > 0 && maxSumLen > 40 {
+			chips = renderChips(names, maxSumLen/3)
+			maxSumLen -= lipgloss.Width(chips) + 1
+		}
 		if maxSumLen < 10 {
 			maxSumLen = 10
 		}
@@ -111,14 +170,25 @@ func (il IssueList) View(width, height int, active bool, selections map[string]b
 		}
 		status := issueStatusStyle.Render(issue.Fields.Status.Name)
 
-		line := fmt.Sprintf("%s%s %s %s", check, key, issueSummaryStyle.Render(summary), status)
+		parts := []string{check + key}
+		if cols != "" {
+			parts = append(parts, helpDescStyle.Render(cols))
+		}
+		parts = append(parts, issueSummaryStyle.Render(summary))
+		if chips != "" {
+			parts = append(parts, chips)
+		}
+		if progress != "" {
+			parts = append(parts, progress)
+		}
+		line := strings.Join(append(parts, status), " ")
 		if i == il.cursor {
 			selPrefix := "  "
 			if selections[issue.Key] {
 				selPrefix = "● "
 			}
 			line = selectedStyle.Width(width - 4).Render(
-				fmt.Sprintf("%s%-12s %s %14s", selPrefix, issue.Key, summary, issue.Fields.Status.Name),
+				fmt.Sprintf("%s%-12s %s%s %s%14s", selPrefix, issue.Key, colPrefix(cols), summary, colPrefix(subtaskProgressText(&issue)), issue.Fields.Status.Name),
 			)
 		}
 		rows = append(rows, line)
@@ -138,13 +208,27 @@ func (il IssueList) View(width, height int, active bool, selections map[string]b
 	return style.Width(width).Height(height).Render(body)
 }
 
+// colPrefix returns cols followed by a separating space, or "" when empty.
+func colPrefix(cols string) string {
+	if cols == "" {
+		return ""
+	}
+	return cols + " "
+}
+
 func (app *App) showTransitions(issueKey string) tea.Cmd {
+	keys := []string{issueKey}
+	for k := range app.selections {
+		keys = append(keys, k)
+	}
+	if app.blocked("transition", keys...) {
+		return nil
+	}
 	return func() tea.Msg {
-		transitions, err := app.client.GetTransitions(issueKey)
+		transitions, err := cache.Transitions(app.client, app.store, issueKey)
 		if err != nil {
-			return nil
+			return statusMsg(fmt.Sprintf("Failed to load transitions: %v", err))
 		}
-		app.store.UpsertTransitions(issueKey, transitions)
 		return transitionsMsg{issueKey: issueKey, transitions: transitions}
 	}
 }
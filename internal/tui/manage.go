@@ -0,0 +1,563 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// issuesRemovedMsg is sent after issues were deleted, archived or moved
+// away, so they are gone from the cache under their old keys.
+type issuesRemovedMsg struct {
+	keys []string
+	text string
+	err  error
+}
+
+// moveTargetMsg carries the issue types and workflows of a move target.
+type moveTargetMsg struct {
+	project  string
+	types    []jira.IssueType
+	statuses map[string][]jira.Status // by issue type ID
+	err      error
+}
+
+// actionIssues returns the selected issues, or the given issue when nothing
+// is selected, read from the cache.
+func (a *App) actionIssues(current *jira.Issue) []jira.Issue {
+	if len(a.selections) == 0 {
+		if current == nil {
+			return nil
+		}
+		return []jira.Issue{*current}
+	}
+	var issues []jira.Issue
+	for key := range a.selections {
+		if issue, err := a.store.GetIssue(key); err == nil {
+			issues = append(issues, *issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Key < issues[j].Key })
+	return issues
+}
+
+func issueProjects(issues []jira.Issue) []string {
+	var projects []string
+	for _, i := range issues {
+		if !contains(projects, i.Fields.Project.Key) {
+			projects = append(projects, i.Fields.Project.Key)
+		}
+	}
+	return projects
+}
+
+// startManage opens the delete or move form ("delete" or "move") for the
+// selected issues or current. Unknown permissions are loaded first and the
+// action is retried when they arrive.
+func (a *App) startManage(action string, current *jira.Issue) tea.Cmd {
+	issues := a.actionIssues(current)
+	if len(issues) == 0 {
+		return nil
+	}
+	projects := issueProjects(issues)
+
+	if _, known := a.permitted(action, projects...); !known {
+		a.pendingManage = action
+		a.pendingIssue = current
+		a.flashMsg = "Checking permissions..."
+		var cmds []tea.Cmd
+		for _, p := range projects {
+			cmds = append(cmds, a.loadPermissions(p))
+		}
+		return tea.Batch(cmds...)
+	}
+	if reason := a.denial(action, projects...); reason != "" {
+		a.flashMsg = reason
+		return nil
+	}
+
+	if action == "move" {
+		for _, i := range issues {
+			if i.Fields.IssueType.Subtask {
+				a.flashMsg = fmt.Sprintf("%s is a sub-task; move its parent instead", i.Key)
+				return nil
+			}
+		}
+		a.moveForm.Show(issues)
+		return nil
+	}
+	canArchive, _ := a.permitted("archive", projects...)
+	a.deleteForm.Show(issues, canArchive)
+	return nil
+}
+
+// handleIssuesRemoved drops removed issues from the selection and leaves
+// the detail view if it showed one of them.
+func (a *App) handleIssuesRemoved(msg issuesRemovedMsg) {
+	if msg.err != nil {
+		a.flashMsg = fmt.Sprintf("Failed: %v", msg.err)
+	} else {
+		a.flashMsg = msg.text
+	}
+	for _, key := range msg.keys {
+		delete(a.selections, key)
+		if a.currentView == viewDetail && a.detail.issue != nil && a.detail.issue.Key == key {
+			a.currentView = viewIssues
+		}
+	}
+	a.loadFromCache()
+}
+
+// DeleteForm confirms deleting or archiving issues by typing the issue key,
+// or the number of issues for a bulk delete.
+type DeleteForm struct {
+	visible      bool
+	issues       []jira.Issue
+	withSubtasks bool
+	canArchive   bool
+	archive      bool
+	confirm      string
+}
+
+// Show opens the form. Subtasks are included by default when any issue has
+// them, since Jira refuses to delete those issues otherwise.
+func (df *DeleteForm) Show(issues []jira.Issue, canArchive bool) {
+	*df = DeleteForm{visible: true, issues: issues, canArchive: canArchive}
+	df.withSubtasks = df.subtaskCount() > 0
+}
+
+func (df *DeleteForm) subtaskCount() int {
+	n := 0
+	for _, i := range df.issues {
+		n += len(i.Fields.Subtasks)
+	}
+	return n
+}
+
+// expected is what has to be typed to confirm.
+func (df *DeleteForm) expected() string {
+	if len(df.issues) == 1 {
+		return df.issues[0].Key
+	}
+	return fmt.Sprint(len(df.issues))
+}
+
+func (df DeleteForm) Update(msg tea.KeyMsg, app *App) (DeleteForm, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		df.visible = false
+	case "tab":
+		df.withSubtasks = !df.withSubtasks
+	case "ctrl+a":
+		df.archive = df.canArchive && !df.archive
+	case "backspace":
+		if len(df.confirm) > 0 {
+			df.confirm = df.confirm[:len(df.confirm)-1]
+		}
+	case "enter":
+		if !strings.EqualFold(strings.TrimSpace(df.confirm), df.expected()) {
+			return df, nil
+		}
+		df.visible = false
+		return df, df.run(app)
+	default:
+		if ch := msg.String(); len(ch) == 1 {
+			df.confirm += ch
+		}
+	}
+	return df, nil
+}
+
+func (df DeleteForm) run(app *App) tea.Cmd {
+	var keys, subtaskKeys []string
+	for _, i := range df.issues {
+		keys = append(keys, i.Key)
+		for _, st := range i.Fields.Subtasks {
+			subtaskKeys = append(subtaskKeys, st.Key)
+		}
+	}
+	archive, withSubtasks := df.archive, df.withSubtasks
+	verb := "Deleting"
+	if archive {
+		verb = "Archiving"
+	}
+	app.flashMsg = fmt.Sprintf("%s %d issues...", verb, len(keys))
+
+	return func() tea.Msg {
+		var removed []string
+		var err error
+		if archive {
+			if err = app.client.ArchiveIssues(keys); err == nil {
+				removed = keys
+			}
+		} else {
+			for _, key := range keys {
+				if err = app.client.DeleteIssue(key, withSubtasks); err != nil {
+					err = fmt.Errorf("%s: %v", key, err)
+					break
+				}
+				removed = append(removed, key)
+			}
+		}
+		if len(removed) == len(keys) && (archive || withSubtasks) {
+			removed = append(removed, subtaskKeys...)
+		}
+		for _, key := range removed {
+			if derr := app.store.DeleteIssue(key); derr != nil && err == nil {
+				err = fmt.Errorf("removing %s from the cache: %w", key, derr)
+			}
+		}
+
+		text := fmt.Sprintf("Deleted %s", strings.Join(keys, ", "))
+		if archive {
+			text = fmt.Sprintf("Archived %s", strings.Join(keys, ", "))
+		}
+		return issuesRemovedMsg{keys: removed, text: text, err: err}
+	}
+}
+
+func (df DeleteForm) View(width, height int) string {
+	title := "Delete "
+	if df.archive {
+		title = "Archive "
+	}
+	if len(df.issues) == 1 {
+		title += df.issues[0].Key
+	} else {
+		title += fmt.Sprintf("%d issues", len(df.issues))
+	}
+
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render(title))
+	lines = append(lines, "")
+	for i, issue := range df.issues {
+		if i == 8 {
+			lines = append(lines, helpDescStyle.Render(fmt.Sprintf("  +%d more", len(df.issues)-8)))
+			break
+		}
+		lines = append(lines, "  "+issueKeyStyle.Render(issue.Key)+" "+truncate(issue.Fields.Summary, 60))
+	}
+	lines = append(lines, "")
+
+	if n := df.subtaskCount(); n > 0 && !df.archive {
+		box := "[ ]"
+		if df.withSubtasks {
+			box = "[x]"
+		}
+		lines = append(lines, fmt.Sprintf("  %s also delete %d subtasks  %s", box, n, helpDescStyle.Render("(Tab)")))
+		if !df.withSubtasks {
+			lines = append(lines, helpDescStyle.Render("      Jira won't delete issues that still have subtasks"))
+		}
+		lines = append(lines, "")
+	}
+	if df.archive {
+		lines = append(lines, helpDescStyle.Render("  Archived issues can be restored by a Jira admin."))
+	} else {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#cc3333")).Bold(true).Render("  This can't be undone."))
+	}
+
+	prompt := fmt.Sprintf("Type %s to confirm: ", df.expected())
+	if len(df.issues) > 1 {
+		prompt = fmt.Sprintf("Type the number of issues (%s) to confirm: ", df.expected())
+	}
+	lines = append(lines, "")
+	lines = append(lines, searchPromptStyle.Render("  "+prompt)+df.confirm+"█")
+	lines = append(lines, "")
+	hints := "  Enter: confirm  Esc: cancel"
+	if df.canArchive {
+		hints += "  Ctrl+A: delete/archive"
+	}
+	lines = append(lines, helpDescStyle.Render(hints))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 80)).Render(strings.Join(lines, "\n")),
+	)
+}
+
+// statusMapping maps one of the moved issues' statuses to a target status.
+type statusMapping struct {
+	from   jira.Status
+	target int // index into the target type's statuses
+}
+
+// MoveForm moves issues to another project: pick the project, the new
+// issue type and a target status for each current status.
+type MoveForm struct {
+	visible  bool
+	issues   []jira.Issue
+	project  string
+	row      int // 0 = project, 1 = type, 2+ = status mappings
+	loading  bool
+	loaded   string // project whose types are loaded
+	types    []jira.IssueType
+	typeIdx  int
+	statuses map[string][]jira.Status
+	mappings []statusMapping
+	errMsg   string
+}
+
+// Show opens the form for the given issues.
+func (mf *MoveForm) Show(issues []jira.Issue) {
+	*mf = MoveForm{visible: true, issues: issues}
+	seen := make(map[string]bool)
+	for _, i := range issues {
+		if s := i.Fields.Status; !seen[s.ID] {
+			seen[s.ID] = true
+			mf.mappings = append(mf.mappings, statusMapping{from: s})
+		}
+	}
+}
+
+func (mf *MoveForm) rowCount() int {
+	if mf.loaded == "" {
+		return 1
+	}
+	return 2 + len(mf.mappings)
+}
+
+// targetStatuses returns the workflow statuses of the chosen issue type.
+func (mf *MoveForm) targetStatuses() []jira.Status {
+	if mf.typeIdx >= len(mf.types) {
+		return nil
+	}
+	return mf.statuses[mf.types[mf.typeIdx].ID]
+}
+
+// autoMap picks, for each current status, the target status with the same
+// name, else the first one in the same category.
+func (mf *MoveForm) autoMap() {
+	targets := mf.targetStatuses()
+	for i := range mf.mappings {
+		m := &mf.mappings[i]
+		m.target = 0
+		best := 0
+		for j, t := range targets {
+			switch {
+			case strings.EqualFold(t.Name, m.from.Name):
+				best, m.target = 2, j
+			case best < 1 && t.StatusCategory != nil && m.from.StatusCategory != nil &&
+				t.StatusCategory.Key == m.from.StatusCategory.Key:
+				best, m.target = 1, j
+			}
+		}
+	}
+}
+
+func (mf *MoveForm) loadTarget(app *App) tea.Cmd {
+	project := strings.TrimSpace(mf.project)
+	if project == "" {
+		return nil
+	}
+	mf.loading = true
+	mf.errMsg = ""
+	return func() tea.Msg {
+		types, err := cache.CreateIssueTypes(app.client, app.store, project)
+		if err != nil {
+			return moveTargetMsg{project: project, err: err}
+		}
+		workflows, err := app.client.GetProjectStatuses(project)
+		if err != nil {
+			return moveTargetMsg{project: project, err: err}
+		}
+		statuses := make(map[string][]jira.Status)
+		for _, w := range workflows {
+			statuses[w.ID] = w.Statuses
+		}
+		var standard []jira.IssueType
+		for _, t := range types {
+			if !t.Subtask {
+				standard = append(standard, t)
+			}
+		}
+		return moveTargetMsg{project: project, types: standard, statuses: statuses}
+	}
+}
+
+// setTarget is called when the target project's types and workflows arrive.
+func (mf *MoveForm) setTarget(msg moveTargetMsg) {
+	if msg.project != strings.TrimSpace(mf.project) {
+		return
+	}
+	mf.loading = false
+	if msg.err != nil {
+		mf.errMsg = msg.err.Error()
+		return
+	}
+	if len(msg.types) == 0 {
+		mf.errMsg = "No issue types you can create in " + msg.project
+		return
+	}
+	mf.loaded = msg.project
+	mf.types = msg.types
+	mf.statuses = msg.statuses
+	mf.typeIdx = 0
+	for i, t := range mf.types {
+		if strings.EqualFold(t.Name, mf.issues[0].Fields.IssueType.Name) {
+			mf.typeIdx = i
+		}
+	}
+	mf.autoMap()
+	mf.row = 1
+}
+
+func (mf MoveForm) Update(msg tea.KeyMsg, app *App) (MoveForm, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		mf.visible = false
+	case "tab", "down":
+		mf.row = (mf.row + 1) % mf.rowCount()
+	case "shift+tab", "up":
+		mf.row = (mf.row - 1 + mf.rowCount()) % mf.rowCount()
+	case "left", "right":
+		delta := 1
+		if msg.String() == "left" {
+			delta = -1
+		}
+		switch {
+		case mf.row == 1:
+			if next := mf.typeIdx + delta; next >= 0 && next < len(mf.types) {
+				mf.typeIdx = next
+				mf.autoMap()
+			}
+		case mf.row >= 2:
+			m := &mf.mappings[mf.row-2]
+			if next := m.target + delta; next >= 0 && next < len(mf.targetStatuses()) {
+				m.target = next
+			}
+		}
+	case "enter", "ctrl+s":
+		if mf.row == 0 && msg.String() == "enter" {
+			mf.loaded = ""
+			return mf, mf.loadTarget(app)
+		}
+		if mf.loading || mf.loaded == "" {
+			return mf, nil
+		}
+		if len(mf.targetStatuses()) == 0 {
+			mf.errMsg = "No workflow found for " + mf.types[mf.typeIdx].Name
+			return mf, nil
+		}
+		mf.visible = false
+		return mf, mf.run(app)
+	case "backspace":
+		if mf.row == 0 && len(mf.project) > 0 {
+			mf.project = mf.project[:len(mf.project)-1]
+		}
+	default:
+		if ch := msg.String(); mf.row == 0 && len(ch) == 1 {
+			mf.project += strings.ToUpper(ch)
+		}
+	}
+	return mf, nil
+}
+
+func (mf MoveForm) run(app *App) tea.Cmd {
+	req := jira.MoveRequest{
+		Project:     mf.loaded,
+		IssueTypeID: mf.types[mf.typeIdx].ID,
+		StatusMap:   make(map[string]string),
+	}
+	var removed []string
+	keyByID := make(map[string]string)
+	for _, i := range mf.issues {
+		req.Keys = append(req.Keys, i.Key)
+		removed = append(removed, i.Key)
+		keyByID[i.ID] = i.Key
+		for _, st := range i.Fields.Subtasks {
+			removed = append(removed, st.Key)
+			keyByID[st.ID] = st.Key
+		}
+	}
+	targets := mf.targetStatuses()
+	for _, m := range mf.mappings {
+		req.StatusMap[m.from.ID] = targets[m.target].ID
+	}
+	app.flashMsg = fmt.Sprintf("Moving %d issues to %s...", len(req.Keys), req.Project)
+
+	move := func() tea.Msg {
+		if err := app.client.MoveIssues(req); err != nil {
+			return issuesRemovedMsg{err: err}
+		}
+		// Moved issues get new keys; rekey them in the cache, then the sync
+		// fetches them as they are now
+		text := fmt.Sprintf("Moved %s to %s", strings.Join(req.Keys, ", "), req.Project)
+		if err := cache.FollowMoves(app.client, app.store, keyByID); err != nil {
+			err = fmt.Errorf("%s, but updating the cache failed (timers and queued worklogs may still use the old keys): %v", text, err)
+			return issuesRemovedMsg{keys: removed, text: text, err: err}
+		}
+		return issuesRemovedMsg{keys: removed, text: text}
+	}
+	app.syncing = true
+	return tea.Sequence(move, app.syncCmd())
+}
+
+func (mf MoveForm) View(width, height int) string {
+	title := "Move "
+	if len(mf.issues) == 1 {
+		title += mf.issues[0].Key
+	} else {
+		title += fmt.Sprintf("%d issues", len(mf.issues))
+	}
+
+	label := func(r int, name string) string {
+		if mf.row == r {
+			return searchPromptStyle.Render(fmt.Sprintf("> %-16s", name))
+		}
+		return fmt.Sprintf("  %-16s", name)
+	}
+	selector := func(r int, value string, i, n int) string {
+		if mf.row != r {
+			return value
+		}
+		return selectedStyle.Render(" ‹ "+value+" › ") + helpDescStyle.Render(fmt.Sprintf("  %d/%d", i+1, n))
+	}
+
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render(title))
+	lines = append(lines, "")
+
+	project := mf.project
+	if mf.row == 0 {
+		project += "█"
+	}
+	lines = append(lines, label(0, "To project:")+" "+project)
+
+	switch {
+	case mf.loading:
+		lines = append(lines, helpDescStyle.Render("  Loading issue types and workflows..."))
+	case mf.loaded != "":
+		t := mf.types[mf.typeIdx]
+		lines = append(lines, label(1, "Issue type:")+" "+selector(1, t.Name, mf.typeIdx, len(mf.types)))
+		lines = append(lines, "")
+		lines = append(lines, helpDescStyle.Render("  Status mapping"))
+		targets := mf.targetStatuses()
+		for i, m := range mf.mappings {
+			value := helpDescStyle.Render("(no workflow)")
+			if m.target < len(targets) {
+				value = selector(2+i, targets[m.target].Name, m.target, len(targets))
+			}
+			lines = append(lines, label(2+i, truncate(m.from.Name, 14)+" →")+" "+value)
+		}
+	}
+
+	if mf.errMsg != "" {
+		lines = append(lines, "")
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#cc3333")).Bold(true).Render("  "+mf.errMsg))
+	}
+	lines = append(lines, "")
+	if mf.loaded == "" {
+		lines = append(lines, helpDescStyle.Render("  Type a project key, Enter: load  Esc: cancel"))
+	} else {
+		lines = append(lines, helpDescStyle.Render("  Sub-tasks move along. Fields missing in the target get defaults."))
+		lines = append(lines, helpDescStyle.Render("  Tab/↑↓: row  Left/Right: choose  Ctrl+S: move  Esc: cancel"))
+	}
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 80)).Render(strings.Join(lines, "\n")),
+	)
+}
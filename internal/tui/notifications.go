@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+)
+
+// maxEvents is how many events the notifications view lists.
+const maxEvents = 200
+
+// NotificationsView lists changes to the user's issues noticed by syncs.
+type NotificationsView struct {
+	events  []cache.Event
+	all     bool // include read events
+	cursor  int
+	summary map[string]string // issue key → summary, from the cache
+	errMsg  string
+}
+
+// Show opens the view on unread events.
+func (nv *NotificationsView) Show(app *App) {
+	nv.all = false
+	nv.cursor = 0
+	nv.load(app)
+}
+
+func (nv *NotificationsView) load(app *App) {
+	events, err := app.store.Events(!nv.all, maxEvents)
+	nv.events = events
+	nv.errMsg = ""
+	if err != nil {
+		nv.errMsg = fmt.Sprintf("Failed to load notifications: %v", err)
+	}
+	nv.summary = make(map[string]string)
+	for _, e := range events {
+		if _, ok := nv.summary[e.IssueKey]; ok {
+			continue
+		}
+		if issue, err := app.store.GetIssue(e.IssueKey); err == nil {
+			nv.summary[e.IssueKey] = issue.Fields.Summary
+		}
+	}
+	if nv.cursor >= len(nv.events) {
+		nv.cursor = max(0, len(nv.events)-1)
+	}
+	app.refreshUnread()
+}
+
+func (nv NotificationsView) selected() *cache.Event {
+	if nv.cursor >= len(nv.events) {
+		return nil
+	}
+	return &nv.events[nv.cursor]
+}
+
+func (nv NotificationsView) Update(msg tea.KeyMsg, app *App) (NotificationsView, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		app.currentView = viewIssues
+	case "down":
+		if nv.cursor < len(nv.events)-1 {
+			nv.cursor++
+		}
+	case "up":
+		if nv.cursor > 0 {
+			nv.cursor--
+		}
+	case "enter":
+		e := nv.selected()
+		if e == nil {
+			return nv, nil
+		}
+		issue, err := app.store.GetIssue(e.IssueKey)
+		if err != nil {
+			app.flashMsg = fmt.Sprintf("%s is no longer cached", e.IssueKey)
+			return nv, nil
+		}
+		if err := app.store.MarkIssueEventsRead(e.IssueKey); err != nil {
+			app.flashMsg = fmt.Sprintf("Marking read failed: %v", err)
+		}
+		nv.load(app)
+		app.detail.SetIssue(issue)
+		app.currentView = viewDetail
+	case "x":
+		if e := nv.selected(); e != nil {
+			if err := app.store.MarkEventRead(e.ID, !e.Read); err != nil {
+				app.flashMsg = fmt.Sprintf("Marking read failed: %v", err)
+			}
+			nv.load(app)
+		}
+	case "A":
+		if err := app.store.MarkAllEventsRead(); err != nil {
+			app.flashMsg = fmt.Sprintf("Marking read failed: %v", err)
+		}
+		nv.load(app)
+	case "tab":
+		nv.all = !nv.all
+		nv.load(app)
+	}
+	return nv, nil
+}
+
+func (nv NotificationsView) View(width, height int) string {
+	var lines []string
+	title := "Notifications"
+	if nv.all {
+		title += " (all)"
+	}
+	lines = append(lines, detailHeaderStyle.Render(title))
+	lines = append(lines, "")
+
+	switch {
+	case nv.errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#cc3333")).Render(nv.errMsg))
+	case len(nv.events) == 0 && nv.all:
+		lines = append(lines, helpDescStyle.Render("No notifications yet"))
+	case len(nv.events) == 0:
+		lines = append(lines, helpDescStyle.Render("No unread notifications"))
+	}
+
+	// Keep the cursor in view
+	visible := max(1, height-8)
+	start := 0
+	if nv.cursor >= visible {
+		start = nv.cursor - visible + 1
+	}
+	for i := start; i < len(nv.events) && i < start+visible; i++ {
+		e := nv.events[i]
+		mark := "●"
+		if e.Read {
+			mark = " "
+		}
+		when := relativeTime(e.Created)
+		head := fmt.Sprintf("%s %-10s %-9s %-8s ", mark, e.IssueKey, e.Kind, when)
+		text := e.Text
+		if s := nv.summary[e.IssueKey]; s != "" {
+			text += "  · " + s
+		}
+		line := head + truncate(text, max(10, width-12-len([]rune(head))))
+		switch {
+		case i == nv.cursor:
+			line = selectedStyle.Width(width - 8).Render(line)
+		case e.Read:
+			line = helpDescStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("↑↓: select  enter: open & mark read  x: read/unread  A: mark all read  tab: all/unread  esc: back"))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Top,
+		panelStyle.Width(width-4).Render(strings.Join(lines, "\n")),
+	)
+}
+
+// relativeTime renders how long ago t was, compactly.
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+// refreshUnread reloads the unread count shown in the header.
+func (a *App) refreshUnread() {
+	if n, err := a.store.UnreadEventCount(); err == nil {
+		a.unread = n
+	}
+}
+
+// renderUnread is the header's unread notifications badge, or "".
+func (a *App) renderUnread() string {
+	if a.unread == 0 {
+		return ""
+	}
+	return helpKeyStyle.Render(fmt.Sprintf("N:%d new", a.unread))
+}
@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// permissionsMsg carries the current user's permissions in a project.
+type permissionsMsg struct {
+	project string
+	perms   map[string]bool
+	err     error
+}
+
+// permAction is a TUI action and the Jira permissions it needs.
+type permAction struct {
+	perms []string
+	what  string // completes "You can't ..."
+	need  string // permission names as shown in Jira's admin screens
+}
+
+var permActions = map[string]permAction{
+	"assign":     {[]string{jira.PermAssignIssues, jira.PermAssignable}, "assign issues to yourself", "Assign Issues and Assignable User"},
+	"transition": {[]string{jira.PermTransitionIssues}, "transition issues", "Transition Issues"},
+	"edit":       {[]string{jira.PermEditIssues}, "edit issues", "Edit Issues"},
+	"comment":    {[]string{jira.PermAddComments}, "comment", "Add Comments"},
+	"log":        {[]string{jira.PermWorkOnIssues}, "log work", "Work On Issues"},
+	"create":     {[]string{jira.PermCreateIssues}, "create issues", "Create Issues"},
+	"attach":     {[]string{jira.PermCreateAttachment}, "attach files", "Create Attachments"},
+	"delete":     {[]string{jira.PermDeleteIssues}, "delete issues", "Delete Issues"},
+	"move":       {[]string{jira.PermMoveIssues}, "move issues", "Move Issues"},
+	"archive":    {[]string{jira.PermAdminister}, "archive issues", "Jira administrator"},
+}
+
+// loadPermissions fetches the user's permissions in a project, through the
+// cache, unless they are known or already loading.
+func (a *App) loadPermissions(project string) tea.Cmd {
+	if _, ok := a.permissions[project]; ok || project == "" || a.permLoading[project] {
+		return nil
+	}
+	a.permLoading[project] = true
+	return func() tea.Msg {
+		perms, err := cache.Permissions(a.client, a.store, project)
+		return permissionsMsg{project: project, perms: perms, err: err}
+	}
+}
+
+// preloadPermissions loads permissions for the default project and every
+// project with cached issues, so hints are right before a key is pressed.
+func (a *App) preloadPermissions() tea.Cmd {
+	projects := []string{a.cfg.DefaultProject}
+	if issues, err := a.store.GetAllIssues(); err == nil {
+		for _, p := range issueProjects(issues) {
+			if !contains(projects, p) {
+				projects = append(projects, p)
+			}
+		}
+	}
+	var cmds []tea.Cmd
+	for _, p := range projects {
+		if cmd := a.loadPermissions(p); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// handlePermissions stores loaded permissions and resumes a waiting delete
+// or move.
+func (a *App) handlePermissions(msg permissionsMsg) tea.Cmd {
+	delete(a.permLoading, msg.project)
+	if msg.err != nil {
+		if a.pendingManage != "" {
+			a.pendingManage = ""
+			a.flashMsg = fmt.Sprintf("Could not check permissions: %v", msg.err)
+		}
+		return nil
+	}
+	a.permissions[msg.project] = msg.perms
+	if a.pendingManage == "" {
+		return nil
+	}
+	issues := a.actionIssues(a.pendingIssue)
+	if _, known := a.permitted(a.pendingManage, issueProjects(issues)...); !known {
+		return nil // other projects still loading
+	}
+	action, current := a.pendingManage, a.pendingIssue
+	a.pendingManage, a.pendingIssue = "", nil
+	a.flashMsg = ""
+	return a.startManage(action, current)
+}
+
+// permitted reports whether an action is allowed in every given project
+// and whether that is known yet.
+func (a *App) permitted(action string, projects ...string) (allowed, known bool) {
+	pa := permActions[action]
+	for _, p := range projects {
+		perms, ok := a.permissions[p]
+		if !ok {
+			return false, false
+		}
+		for _, perm := range pa.perms {
+			if !perms[perm] {
+				return false, true
+			}
+		}
+	}
+	return true, true
+}
+
+// denial explains why an action isn't allowed in some of the projects, or
+// returns "" when it is allowed or not known yet.
+func (a *App) denial(action string, projects ...string) string {
+	var denied []string
+	for _, p := range projects {
+		if allowed, known := a.permitted(action, p); known && !allowed {
+			denied = append(denied, p)
+		}
+	}
+	if len(denied) == 0 {
+		return ""
+	}
+	pa := permActions[action]
+	return fmt.Sprintf("You can't %s in %s: needs the %s permission", pa.what, strings.Join(denied, ", "), pa.need)
+}
+
+// blocked shows why an action is not allowed on the given issue keys and
+// returns true, or returns false when it may go ahead. Actions whose
+// permissions aren't loaded yet go ahead and Jira has the final word.
+func (a *App) blocked(action string, keys ...string) bool {
+	var projects []string
+	for _, k := range keys {
+		if p := projectOf(k); !contains(projects, p) {
+			projects = append(projects, p)
+		}
+	}
+	if reason := a.denial(action, projects...); reason != "" {
+		a.flashMsg = reason
+		return true
+	}
+	return false
+}
+
+// projectOf returns the project key part of an issue key.
+func projectOf(issueKey string) string {
+	if i := strings.LastIndex(issueKey, "-"); i > 0 {
+		return issueKey[:i]
+	}
+	return issueKey
+}
+
+// hint renders a "key:label" footer hint, greyed out when the action is
+// known to be denied in any of the projects.
+func (a *App) hint(key, label, action string, projects ...string) string {
+	text := key + ":" + label
+	if action != "" {
+		if allowed, known := a.permitted(action, projects...); known && !allowed {
+			return disabledHintStyle.Render(text)
+		}
+	}
+	return helpDescStyle.Render(text)
+}
+
+// hints joins rendered hints into a footer line.
+func hints(parts ...string) string {
+	return strings.Join(parts, helpDescStyle.Render("  "))
+}
+
+// selectionProjects returns the projects of the selected issues.
+func (a *App) selectionProjects() []string {
+	var projects []string
+	for key := range a.selections {
+		if p := projectOf(key); !contains(projects, p) {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// headerHints are the key hints in the header for the list and board.
+func (a *App) headerHints() string {
+	if n := a.selectionCount(); n > 0 {
+		projects := a.selectionProjects()
+		return helpKeyStyle.Render(fmt.Sprintf("[%d selected]", n)) + "  " + hints(
+			a.hint("m", "move all", "transition", projects...),
+			a.hint("X", "delete", "delete", projects...),
+			a.hint("M", "to project", "move", projects...),
+			a.hint("space", "toggle", ""),
+			a.hint("esc", "clear", ""),
+		)
+	}
+
+	var projects []string
+	if issue := a.focusedIssue(); issue != nil {
+		projects = []string{issue.Fields.Project.Key}
+	}
+	return hints(
+		a.hint("enter", "open", ""),
+		a.hint("n", "new", "create", a.cfg.DefaultProject),
+		a.hint("f", "filter", ""),
+		a.hint("p", "project", ""),
+		a.hint("o", "browser", ""),
+		a.hint("a", "assign", "assign", projects...),
+		a.hint("m", "move", "transition", projects...),
+		a.hint("?", "help", ""),
+	)
+}
+
+// detailHints are the footer hints of the detail view's current tab.
+func (a *App) detailHints() string {
+	dv := &a.detail
+	if dv.issue == nil {
+		return ""
+	}
+	p := dv.issue.Fields.Project.Key
+	switch dv.tab {
+	case tabWorklog:
+		return hints(a.hint("esc", "back", ""), a.hint("tab", "next tab", ""), a.hint("t", "log", "log", p),
+			a.hint("e", "edit", "log", p), a.hint("x", "delete", "log", p), helpDescStyle.Render("(* = yours)"), a.hint("?", "help", ""))
+	case tabAttachments:
+		return hints(a.hint("esc", "back", ""), a.hint("tab", "next tab", ""), a.hint("enter", "open", ""),
+			a.hint("d", "download", ""), a.hint("u", "upload", "attach", p), a.hint("i", "preview", ""), a.hint("?", "help", ""))
+	case tabSubtasks:
+		return hints(a.hint("esc", "back", ""), a.hint("tab", "next tab", ""), a.hint("n", "add", "create", p),
+			a.hint("x", "done/undo", "transition", p), a.hint("enter", "open", ""), a.hint("?", "help", ""))
+	}
+
+	parts := []string{
+		a.hint("esc", "back", ""), a.hint("tab", "next tab", ""), a.hint("o", "browser", ""),
+		a.hint("a", "assign", "assign", p), a.hint("c", "comment", "comment", p), a.hint("t", "log", "log", p),
+		a.hint("s", "timer", ""), a.hint("m", "move", "transition", p), a.hint("w", "watch", ""), a.hint("v", "vote", ""),
+		a.hint("l/C/V", "fields", "edit", p), a.hint("Y", "clone", "create", p), a.hint("S", "split", "create", p),
+	}
+	// Delete and move are hidden rather than greyed out without permission
+	if ok, _ := a.permitted("delete", p); ok {
+		parts = append(parts, a.hint("X", "delete", ""))
+	}
+	if ok, _ := a.permitted("move", p); ok && !dv.issue.Fields.IssueType.Subtask {
+		parts = append(parts, a.hint("M", "move project", ""))
+	}
+	return hints(append(parts, a.hint("?", "help", ""))...)
+}
+
+// focusedIssue returns the issue under the cursor of the active panel.
+func (a *App) focusedIssue() *jira.Issue {
+	if a.activePanel == 1 {
+		return a.board.SelectedIssue()
+	}
+	return a.issues.SelectedIssue()
+}
@@ -6,7 +6,6 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/temujinlabs/shinkansen/internal/cache"
 	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
@@ -70,21 +69,7 @@ func (pp ProjectPicker) Update(msg tea.Msg, app *App) (ProjectPicker, tea.Cmd) {
 			if len(pp.projects) > 0 && pp.cursor < len(pp.projects) {
 				project := pp.projects[pp.cursor]
 				pp.Hide()
-
-				// If same project, do nothing
-				if project.Key == app.cfg.DefaultProject {
-					return pp, nil
-				}
-
-				newProjectKey := project.Key
-				return pp, func() tea.Msg {
-					// Clear cache for old project and sync new one
-					result := cache.Sync(app.client, app.store, newProjectKey)
-					if result.Err != nil {
-						return statusMsg(fmt.Sprintf("Switch failed: %v", result.Err))
-					}
-					return projectSwitchedMsg{projectKey: newProjectKey}
-				}
+				return pp, func() tea.Msg { return projectSwitchedMsg{projectKey: project.Key} }
 			}
 			return pp, nil
 		}
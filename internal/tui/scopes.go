@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// nextScope narrows the list and board to the next sync scope, wrapping
+// around to all cached issues.
+func (a *App) nextScope() {
+	scopes := a.cfg.Scopes()
+	next := ""
+	if a.scope == "" {
+		next = scopes[0].Name
+	} else {
+		for i, s := range scopes {
+			if s.Name == a.scope && i+1 < len(scopes) {
+				next = scopes[i+1].Name
+			}
+		}
+	}
+	a.scope = next
+	a.issues.cursor, a.issues.offset = 0, 0
+	a.loadFromCache()
+	if next == "" {
+		a.flashMsg = "Showing all scopes"
+	} else {
+		a.flashMsg = fmt.Sprintf("Showing scope %s (%d issues)", next, len(a.issues.issues))
+	}
+}
+
+// scopeIssues returns the cached issues in the selected scope.
+func (a *App) scopeIssues() ([]jira.Issue, error) {
+	if a.scope == "" {
+		return a.store.GetAllIssues()
+	}
+	return a.store.GetScopeIssues(a.scope)
+}
+
+// renderScope shows the selected scope in the header.
+func (a *App) renderScope() string {
+	if a.scope == "" {
+		return ""
+	}
+	return helpKeyStyle.Render("g:" + a.scope)
+}
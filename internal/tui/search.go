@@ -7,12 +7,11 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/temujinlabs/shinkansen/internal/cache"
-	"github.com/temujinlabs/shinkansen/internal/jira"
 )
 
 type SearchView struct {
 	query    string
-	results  []jira.Issue
+	results  []cache.SearchHit
 	cursor   int
 	creating bool // true when in "new issue" mode
 }
@@ -49,9 +48,10 @@ func (sv SearchView) Update(msg tea.Msg, app *App) (SearchView, tea.Cmd) {
 					projectKey := app.cfg.DefaultProject
 					sv.Reset()
 					app.currentView = viewIssues
+					resync := app.syncCmd()
 					return sv, func() tea.Msg {
 						app.client.CreateIssue(projectKey, summary, "Task")
-						return syncDoneMsg{result: cache.Sync(app.client, app.store, app.cfg.DefaultProject)}
+						return resync()
 					}
 				}
 				return sv, nil
@@ -59,7 +59,7 @@ func (sv SearchView) Update(msg tea.Msg, app *App) (SearchView, tea.Cmd) {
 
 			// Select a search result
 			if len(sv.results) > 0 && sv.cursor < len(sv.results) {
-				issue := sv.results[sv.cursor]
+				issue := sv.results[sv.cursor].Issue
 				app.detail.SetIssue(&issue)
 				app.currentView = viewDetail
 				sv.Reset()
@@ -100,12 +100,12 @@ func (sv *SearchView) doSearch(app *App) {
 		return
 	}
 
-	issues, err := app.store.SearchIssues(sv.query)
+	hits, err := app.store.SearchIssues(sv.query)
 	if err != nil {
 		sv.results = nil
 		return
 	}
-	sv.results = issues
+	sv.results = hits
 	sv.cursor = 0
 }
 
@@ -124,25 +124,38 @@ func (sv SearchView) View(width, height int) string {
 			lines = append(lines, helpDescStyle.Render("No results"))
 		}
 
-		maxResults := height - 6
-		for i, issue := range sv.results {
-			if i >= maxResults {
-				lines = append(lines, helpDescStyle.Render(fmt.Sprintf("  +%d more", len(sv.results)-maxResults)))
+		room := height - 6
+		for i, hit := range sv.results {
+			need := 1
+			if hit.Snippet != "" {
+				need = 2
+			}
+			if room < need {
+				lines = append(lines, helpDescStyle.Render(fmt.Sprintf("  +%d more", len(sv.results)-i)))
 				break
 			}
+			room -= need
 
-			line := fmt.Sprintf("  %s  %s  [%s]", issue.Key, issue.Fields.Summary, issue.Fields.Status.Name)
-			if len(line) > width-4 {
-				line = line[:width-7] + "..."
+			mark := "  "
+			if hit.Fuzzy {
+				mark = "~ " // key or title matched loosely
 			}
+			prefix := fmt.Sprintf("%s%s  ", mark, hit.Issue.Key)
+			suffix := fmt.Sprintf("  [%s]", hit.Issue.Fields.Status.Name)
+			title := truncateMarked(hit.Title, width-8-len([]rune(prefix))-len([]rune(suffix)))
 			if i == sv.cursor {
-				line = selectedStyle.Width(width - 4).Render(line)
+				lines = append(lines, selectedStyle.Width(width-4).Render(prefix+stripMarks(title)+suffix))
+			} else {
+				lines = append(lines, prefix+renderMarked(title, lipgloss.NewStyle())+suffix)
+			}
+			if hit.Snippet != "" {
+				snippet := strings.Join(strings.Fields(hit.Snippet), " ")
+				lines = append(lines, "      "+renderMarked(truncateMarked(snippet, width-14), helpDescStyle))
 			}
-			lines = append(lines, line)
 		}
 
 		lines = append(lines, "")
-		lines = append(lines, helpDescStyle.Render("Enter: select  Esc: cancel"))
+		lines = append(lines, helpDescStyle.Render("Enter: select  Esc: cancel  words match summaries, descriptions, comments  ~ fuzzy  label:x component:x version:x <custom field>:x filter"))
 	}
 
 	content := strings.Join(lines, "\n")
@@ -150,3 +163,55 @@ func (sv SearchView) View(width, height int) string {
 		panelStyle.Width(width-4).Render(content),
 	)
 }
+
+// renderMarked styles text, with the matches between cache.MatchStart and
+// cache.MatchEnd highlighted.
+func renderMarked(text string, base lipgloss.Style) string {
+	var b strings.Builder
+	for text != "" {
+		before, rest, found := strings.Cut(text, cache.MatchStart)
+		if before != "" {
+			b.WriteString(base.Render(before))
+		}
+		if !found {
+			break
+		}
+		match, after, _ := strings.Cut(rest, cache.MatchEnd)
+		b.WriteString(searchMatchStyle.Render(match))
+		text = after
+	}
+	return b.String()
+}
+
+// stripMarks removes match markers.
+func stripMarks(text string) string {
+	return strings.NewReplacer(cache.MatchStart, "", cache.MatchEnd, "").Replace(text)
+}
+
+// truncateMarked cuts marked text to n visible runes, closing a match cut
+// in half.
+func truncateMarked(text string, n int) string {
+	if len([]rune(stripMarks(text))) <= n {
+		return text
+	}
+	var b strings.Builder
+	visible, open := 0, false
+	for _, r := range text {
+		switch string(r) {
+		case cache.MatchStart:
+			open = true
+		case cache.MatchEnd:
+			open = false
+		default:
+			if visible >= n-3 {
+				if open {
+					b.WriteString(cache.MatchEnd)
+				}
+				return b.String() + "..."
+			}
+			visible++
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -73,6 +73,36 @@ var (
 	detailValueStyle = lipgloss.NewStyle().
 				Foreground(colorPrimary)
 
+	// Detail tabs
+	tabStyle = lipgloss.NewStyle().
+			Foreground(colorSubtle).
+			Padding(0, 1)
+
+	activeTabStyle = lipgloss.NewStyle().
+			Foreground(colorWhite).
+			Background(colorAccent).
+			Bold(true).
+			Padding(0, 1)
+
+	// Diffs
+	diffAddStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#4f7942"))
+
+	diffDelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#cc3333"))
+
+	// Subtask progress
+	progressDoneStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#4f7942"))
+
+	progressTodoStyle = lipgloss.NewStyle().
+				Foreground(colorSubtle)
+
+	// Chips (labels, components, fix versions)
+	chipStyle = lipgloss.NewStyle().
+			Foreground(colorWhite).
+			Padding(0, 1)
+
 	// Help
 	helpKeyStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -81,13 +111,35 @@ var (
 	helpDescStyle = lipgloss.NewStyle().
 			Foreground(colorSubtle)
 
+	// Hints for actions the user has no permission for
+	disabledHintStyle = lipgloss.NewStyle().
+				Foreground(colorSubtle).
+				Faint(true).
+				Strikethrough(true)
+
 	// Search
 	searchPromptStyle = lipgloss.NewStyle().
 				Foreground(colorCTA).
 				Bold(true)
 
+	// Matched text in search results
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(colorAccent).
+				Bold(true).
+				Underline(true)
+
 	// Selection indicator
 	selectedCheckStyle = lipgloss.NewStyle().
 				Foreground(colorCTA).
 				Bold(true)
 )
+
+// chipColors are picked per value so the same label always has the same colour.
+var chipColors = []lipgloss.Color{
+	"#4f7942", // Cossack Green
+	"#2d5c7f", // Dusky Blue
+	"#7c4b73", // Dark Vinaceous
+	"#ae5224", // Burnt Sienna
+	"#5e6b3a", // Olive
+	"#8b5a2b", // Raw Umber
+}
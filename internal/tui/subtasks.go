@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// selectedSubtask returns the subtask under the cursor in the subtasks tab.
+func (dv *DetailView) selectedSubtask() *jira.Issue {
+	if dv.issue == nil || dv.subtaskCursor >= len(dv.issue.Fields.Subtasks) {
+		return nil
+	}
+	return &dv.issue.Fields.Subtasks[dv.subtaskCursor]
+}
+
+// refreshIssue reloads an issue from Jira into the cache. An issue that
+// can't be fetched is left to the next sync; failing to save one is an error.
+func refreshIssue(app *App, key string) error {
+	issue, err := app.client.GetIssue(key)
+	if err != nil {
+		return nil
+	}
+	if err := app.store.UpsertIssue(issue); err != nil {
+		return fmt.Errorf("saving %s to the cache: %w", key, err)
+	}
+	return nil
+}
+
+// addSubtask creates a subtask under the open issue using the project's
+// sub-task type from createmeta.
+func (dv DetailView) addSubtask(summary string, app *App) tea.Cmd {
+	parent := dv.issue
+	return func() tea.Msg {
+		if parent.Fields.IssueType.Subtask {
+			return issueActionMsg{issueKey: parent.Key, err: fmt.Errorf("sub-tasks can't have subtasks")}
+		}
+		project := parent.Fields.Project.Key
+		issueType, err := cache.SubtaskType(app.client, app.store, project)
+		if err != nil {
+			return issueActionMsg{issueKey: parent.Key, err: err}
+		}
+		extra := map[string]interface{}{"parent": map[string]string{"key": parent.Key}}
+		sub, err := app.client.CreateIssueWithDetails(project, summary, issueType, "", "", extra)
+		if err != nil {
+			return issueActionMsg{issueKey: parent.Key, err: err}
+		}
+		for _, k := range []string{sub.Key, parent.Key} {
+			if err := refreshIssue(app, k); err != nil {
+				return issueActionMsg{issueKey: parent.Key, err: err}
+			}
+		}
+		return issueActionMsg{issueKey: parent.Key, text: "Created " + sub.Key}
+	}
+}
+
+// toggleSubtask moves the selected subtask to a done status, or back to a
+// to-do status when it is already done.
+func (dv DetailView) toggleSubtask(app *App) tea.Cmd {
+	st := dv.selectedSubtask()
+	if st == nil {
+		return nil
+	}
+	parentKey := dv.issue.Key
+	key := st.Key
+	reopen := st.Fields.Status.IsDone()
+	return func() tea.Msg {
+		transitions, err := app.client.GetTransitions(key)
+		if err != nil {
+			return issueActionMsg{issueKey: parentKey, err: err}
+		}
+		want, wantName := "done", "done"
+		if reopen {
+			want, wantName = "new", "to-do"
+		}
+		var target *jira.Transition
+		for i, t := range transitions {
+			if c := t.To.StatusCategory; c != nil && c.Key == want {
+				target = &transitions[i]
+				break
+			}
+		}
+		if target == nil {
+			return issueActionMsg{issueKey: parentKey, err: fmt.Errorf("%s has no transition to a %s status", key, wantName)}
+		}
+		err = app.client.TransitionIssue(key, target.ID)
+		if ierr := app.store.InvalidateTransitions(key); err == nil {
+			err = ierr
+		}
+		if err != nil {
+			return issueActionMsg{issueKey: parentKey, err: err}
+		}
+		for _, k := range []string{key, parentKey} {
+			if err := refreshIssue(app, k); err != nil {
+				return issueActionMsg{issueKey: parentKey, err: err}
+			}
+		}
+		return issueActionMsg{issueKey: parentKey, text: fmt.Sprintf("%s → %s", key, target.To.Name)}
+	}
+}
+
+func (dv DetailView) renderSubtasks(width int) []string {
+	var lines []string
+	subs := dv.issue.Fields.Subtasks
+	if len(subs) == 0 {
+		lines = append(lines, helpDescStyle.Render("No subtasks"))
+	} else {
+		lines = append(lines, subtaskProgress(dv.issue, 20))
+		lines = append(lines, "")
+	}
+	for i, st := range subs {
+		box := "☐"
+		if st.Fields.Status.IsDone() {
+			box = "☑"
+		}
+		line := fmt.Sprintf("%s %-10s %s", box, st.Key, st.Fields.Summary)
+		line = truncate(line, width-24) + "  " + issueStatusStyle.Render(st.Fields.Status.Name)
+		if i == dv.subtaskCursor {
+			line = selectedStyle.Width(width - 6).Render(fmt.Sprintf("%s %-10s %s  %s", box, st.Key, truncate(st.Fields.Summary, width-40), st.Fields.Status.Name))
+		}
+		lines = append(lines, line)
+	}
+
+	if dv.addingSubtask {
+		lines = append(lines, "")
+		lines = append(lines, searchPromptStyle.Render("New subtask: ")+dv.subtaskBuf+"█")
+		lines = append(lines, helpDescStyle.Render("  Enter: create (stays open for the next)  Esc: done"))
+	}
+	return lines
+}
+
+// subtaskProgress renders "done/total" with a small bar, or "" when the
+// issue has no subtasks. barWidth 0 leaves out the bar.
+func subtaskProgress(issue *jira.Issue, barWidth int) string {
+	done, total := issue.SubtaskProgress()
+	if total == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%d/%d", done, total)
+	if barWidth > 0 {
+		filled := done * barWidth / total
+		text = progressDoneStyle.Render(strings.Repeat("█", filled)) +
+			progressTodoStyle.Render(strings.Repeat("░", barWidth-filled)) + " " + text
+	}
+	if done == total {
+		return progressDoneStyle.Render("✓") + " " + text
+	}
+	return text
+}
+
+// subtaskProgressText is subtaskProgress without styling, for rows drawn in
+// the selection colour.
+func subtaskProgressText(issue *jira.Issue) string {
+	done, total := issue.SubtaskProgress()
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", done, total)
+}
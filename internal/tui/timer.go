@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// timerHeartbeat is how often a running TUI records that it is alive. Gaps
+// longer than the configured idle time (e.g. a suspended laptop) pause the timer.
+const timerHeartbeat = 30 * time.Second
+
+type timerTickMsg time.Time
+
+func (a *App) timerTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return timerTickMsg(t)
+	})
+}
+
+// startTimerTicks begins the once-a-second header refresh unless it already runs.
+func (a *App) startTimerTicks() tea.Cmd {
+	if a.timerTicking {
+		return nil
+	}
+	a.timerTicking = true
+	return a.timerTickCmd()
+}
+
+// handleTimerTick reloads the timer (the CLI may have changed it), applies
+// idle detection and keeps ticking while a timer exists.
+func (a *App) handleTimerTick(now time.Time) tea.Cmd {
+	t, err := a.store.ActiveTimer()
+	if err != nil || t == nil {
+		a.timer = nil
+		a.timerTicking = false
+		a.timerBeating = false
+		return nil
+	}
+
+	switch {
+	case !t.Running():
+		a.timerBeating = false
+	case !a.timerBeating:
+		// Time before this TUI saw the timer running (started from the CLI,
+		// or while no TUI was open) isn't known to be idle
+		a.timerBeating = a.store.TouchTimer(now) == nil
+	default:
+		idle := time.Duration(a.cfg.TimerIdleMinutes) * time.Minute
+		lastSeen := t.LastSeen
+		if lastSeen.Before(t.RunningSince) {
+			lastSeen = t.RunningSince
+		}
+		if gap := now.Sub(lastSeen); idle > 0 && gap > idle {
+			if err := a.store.PauseTimer(lastSeen); err != nil {
+				a.flashMsg = fmt.Sprintf("Timer idle for %s, but pausing failed: %v", jira.FormatDuration(gap), err)
+				break
+			}
+			a.flashMsg = fmt.Sprintf("Timer idle for %s, paused without it (P to resume)", jira.FormatDuration(gap))
+			t, _ = a.store.ActiveTimer()
+		} else if now.Sub(t.LastSeen) >= timerHeartbeat {
+			// A failed heartbeat is retried on the next tick
+			a.store.TouchTimer(now)
+		}
+	}
+	a.timer = t
+	return a.timerTickCmd()
+}
+
+// toggleTimer starts a timer on an issue, or stops the one running on it.
+func (a *App) toggleTimer(issueKey string) tea.Cmd {
+	t, err := a.store.ActiveTimer()
+	if err != nil {
+		a.flashMsg = fmt.Sprintf("Timer error: %v", err)
+		return nil
+	}
+	if t != nil {
+		if t.IssueKey != issueKey {
+			a.flashMsg = fmt.Sprintf("Timer already running on %s; stop it there first", t.IssueKey)
+			return nil
+		}
+		a.stopTimer()
+		return nil
+	}
+
+	if err := a.store.StartTimer(issueKey, time.Now()); err != nil {
+		a.flashMsg = fmt.Sprintf("Timer error: %v", err)
+		return nil
+	}
+	a.timer, _ = a.store.ActiveTimer()
+	a.flashMsg = fmt.Sprintf("Timer started on %s", issueKey)
+	return a.startTimerTicks()
+}
+
+// stopTimer pauses the timer and opens a log work form pre-filled with the
+// rounded elapsed time, starting when the timer was first started. The
+// timer is only removed once the work is logged or queued, or the form is
+// dismissed with Esc, so a rejected worklog doesn't lose the tracked time.
+func (a *App) stopTimer() {
+	now := time.Now()
+	if err := a.store.PauseTimer(now); err != nil {
+		a.flashMsg = fmt.Sprintf("Timer error: %v", err)
+		return
+	}
+	t, err := a.store.ActiveTimer()
+	if err != nil || t == nil {
+		return
+	}
+	a.timer = t
+
+	increment := time.Duration(a.cfg.TimerRoundMinutes) * time.Minute
+	spent := cache.RoundDuration(t.Elapsed(now), increment)
+
+	if a.detail.issue == nil || a.detail.issue.Key != t.IssueKey {
+		issue, err := a.store.GetIssue(t.IssueKey)
+		if err != nil {
+			issue = &jira.Issue{Key: t.IssueKey}
+		}
+		a.detail.SetIssue(issue)
+	}
+	a.currentView = viewDetail
+	a.detail.logging = true
+	a.detail.logForm.ShowTimer(t.IssueKey, jira.FormatDuration(spent), t.StartedAt.Local())
+	a.flashMsg = fmt.Sprintf("Timer paused at %s: Enter logs it, Esc discards it", jira.FormatDuration(t.Elapsed(now)))
+}
+
+// finishTimer removes the timer on issueKey after its time was logged. A
+// timer restarted or moved to another issue meanwhile is left alone.
+func (a *App) finishTimer(issueKey string) error {
+	t, err := a.store.ActiveTimer()
+	if err != nil || t == nil || t.IssueKey != issueKey {
+		return err
+	}
+	if _, err := a.store.StopTimer(time.Now()); err != nil {
+		return err
+	}
+	a.timer = nil
+	return nil
+}
+
+// discardTimer removes the timer on issueKey without logging its time.
+func (a *App) discardTimer(issueKey string) {
+	if err := a.finishTimer(issueKey); err != nil {
+		a.flashMsg = fmt.Sprintf("Timer error: %v", err)
+		return
+	}
+	a.flashMsg = fmt.Sprintf("Timer on %s discarded", issueKey)
+}
+
+// togglePauseTimer pauses a running timer or resumes a paused one.
+func (a *App) togglePauseTimer() {
+	t, err := a.store.ActiveTimer()
+	if err != nil || t == nil {
+		a.flashMsg = "No timer running (s to start one)"
+		return
+	}
+	if t.Running() {
+		err = a.store.PauseTimer(time.Now())
+		a.flashMsg = fmt.Sprintf("Timer on %s paused", t.IssueKey)
+	} else {
+		err = a.store.ResumeTimer(time.Now())
+		a.flashMsg = fmt.Sprintf("Timer on %s resumed", t.IssueKey)
+	}
+	if err != nil {
+		a.flashMsg = fmt.Sprintf("Timer error: %v", err)
+	}
+	a.timer, _ = a.store.ActiveTimer()
+}
+
+// renderTimer returns the header segment for the active timer, if any.
+func (a *App) renderTimer() string {
+	if a.timer == nil {
+		return ""
+	}
+	elapsed := a.timer.Elapsed(time.Now())
+	h := int(elapsed.Hours())
+	m := int(elapsed.Minutes()) % 60
+	sec := int(elapsed.Seconds()) % 60
+	icon := "⏱"
+	if !a.timer.Running() {
+		icon = "⏸"
+	}
+	return helpKeyStyle.Render(fmt.Sprintf("%s %s %d:%02d:%02d", icon, a.timer.IssueKey, h, m, sec))
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// timesheetLoadedMsg is sent after a week of worklogs has been aggregated.
+type timesheetLoadedMsg struct {
+	weekStart time.Time
+	sheet     *jira.Timesheet
+	err       error
+}
+
+var weekdayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// TimesheetView shows the current user's logged time as an issue × weekday grid.
+type TimesheetView struct {
+	weekStart time.Time
+	sheet     *jira.Timesheet
+	loading   bool
+	errMsg    string
+	row       int
+	col       int // weekday, 0 = Monday
+	form      WorklogForm
+}
+
+func NewTimesheetView() TimesheetView {
+	return TimesheetView{weekStart: jira.WeekStart(time.Now())}
+}
+
+// Show opens the timesheet on the current week and starts loading it.
+func (tv *TimesheetView) Show(app *App) tea.Cmd {
+	tv.weekStart = jira.WeekStart(time.Now())
+	tv.row = 0
+	tv.col = (int(time.Now().Weekday()) + 6) % 7
+	return tv.load(app)
+}
+
+func (tv *TimesheetView) load(app *App) tea.Cmd {
+	tv.loading = true
+	tv.errMsg = ""
+	weekStart := tv.weekStart
+	accountID := app.cfg.AccountID
+	return func() tea.Msg {
+		sheet, err := app.client.Timesheet(accountID, weekStart)
+		if err == nil {
+			// Offer rows for work in progress so time can be logged on it too
+			issues, _ := app.store.GetAllIssues()
+			for _, issue := range issues {
+				if issue.Fields.Assignee != nil && issue.Fields.Assignee.AccountID == accountID && inProgress(issue.Fields.Status.Name) {
+					sheet.AddRow(issue.Key, issue.Fields.Summary)
+				}
+			}
+		}
+		return timesheetLoadedMsg{weekStart: weekStart, sheet: sheet, err: err}
+	}
+}
+
+// inProgress reports whether a status belongs in the board's middle column.
+func inProgress(status string) bool {
+	s := strings.ToLower(status)
+	return strings.Contains(s, "progress") || strings.Contains(s, "review")
+}
+
+func (tv TimesheetView) Update(msg tea.Msg, app *App) (TimesheetView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if tv.form.visible {
+			var cmd tea.Cmd
+			tv.form, cmd = tv.form.Update(msg, app)
+			return tv, cmd
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			app.currentView = viewIssues
+		case "down":
+			if tv.sheet != nil && tv.row < len(tv.sheet.Rows)-1 {
+				tv.row++
+			}
+		case "up":
+			if tv.row > 0 {
+				tv.row--
+			}
+		case "left":
+			if tv.col > 0 {
+				tv.col--
+			}
+		case "right":
+			if tv.col < 6 {
+				tv.col++
+			}
+		case "[":
+			tv.weekStart = tv.weekStart.AddDate(0, 0, -7)
+			return tv, tv.load(app)
+		case "]":
+			tv.weekStart = tv.weekStart.AddDate(0, 0, 7)
+			return tv, tv.load(app)
+		case "enter":
+			if tv.sheet == nil || tv.row >= len(tv.sheet.Rows) {
+				return tv, nil
+			}
+			// Start new entries at 09:00 on past days, or now minus the
+			// duration on today.
+			key := tv.sheet.Rows[tv.row].IssueKey
+			day := tv.sheet.Day(tv.col)
+			if jira.WeekStart(time.Now()).Equal(tv.weekStart) && tv.col == (int(time.Now().Weekday())+6)%7 {
+				tv.form.Show(key)
+			} else {
+				tv.form.ShowPrefilled(key, "", day.Add(9*time.Hour))
+			}
+		}
+	}
+	return tv, nil
+}
+
+func (tv TimesheetView) View(width, height int, targetHours float64) string {
+	if tv.form.visible {
+		return tv.form.View(width, height)
+	}
+
+	var lines []string
+	weekEnd := tv.weekStart.AddDate(0, 0, 6)
+	lines = append(lines, detailHeaderStyle.Render(fmt.Sprintf("Timesheet: %s – %s",
+		tv.weekStart.Format("Mon Jan 2"), weekEnd.Format("Mon Jan 2, 2006"))))
+
+	switch {
+	case tv.loading && tv.sheet == nil:
+		lines = append(lines, helpDescStyle.Render("Loading worklogs..."))
+	case tv.errMsg != "":
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#cc3333")).Render(tv.errMsg))
+	case tv.sheet != nil:
+		lines = append(lines, tv.renderGrid(width, targetHours)...)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("↑↓←→: cell  enter: log work in cell  [ ]: prev/next week  esc: back"))
+
+	content := strings.Join(lines, "\n")
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Top,
+		panelStyle.Width(width-4).Render(content),
+	)
+}
+
+func (tv TimesheetView) renderGrid(width int, targetHours float64) []string {
+	const cellWidth = 7
+	labelWidth := width - 10 - cellWidth*8
+	if labelWidth < 14 {
+		labelWidth = 14
+	}
+
+	var lines []string
+	header := fmt.Sprintf("%-*s", labelWidth, "")
+	for d, name := range weekdayNames {
+		header += fmt.Sprintf("%*s", cellWidth, fmt.Sprintf("%s %d", name, tv.sheet.Day(d).Day()))
+	}
+	header += fmt.Sprintf("%*s", cellWidth, "Total")
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render(header))
+
+	if len(tv.sheet.Rows) == 0 {
+		lines = append(lines, helpDescStyle.Render("No work logged this week"))
+	}
+
+	cell := func(d time.Duration) string {
+		if d == 0 {
+			return fmt.Sprintf("%*s", cellWidth, "·")
+		}
+		return fmt.Sprintf("%*s", cellWidth, jira.FormatHours(d))
+	}
+
+	for ri, r := range tv.sheet.Rows {
+		line := fmt.Sprintf("%-*s", labelWidth, truncate(r.IssueKey+"  "+r.Summary, labelWidth-1))
+		for d := 0; d < 7; d++ {
+			c := cell(r.Days[d])
+			if ri == tv.row && d == tv.col {
+				c = selectedStyle.Render(c)
+			}
+			line += c
+		}
+		line += cell(r.Total())
+		lines = append(lines, line)
+	}
+
+	// Day totals, highlighting past weekdays below the target
+	today := time.Now()
+	totals := fmt.Sprintf("%-*s", labelWidth, "Total")
+	for d := 0; d < 7; d++ {
+		c := cell(tv.sheet.DayTotal(d))
+		day := tv.sheet.Day(d)
+		short := targetHours > 0 && d < 5 && day.Before(today) &&
+			tv.sheet.DayTotal(d) < time.Duration(targetHours*float64(time.Hour))
+		if short {
+			c = diffDelStyle.Bold(true).Render(c)
+		} else {
+			c = helpKeyStyle.Render(c)
+		}
+		totals += c
+	}
+	totals += helpKeyStyle.Render(cell(tv.sheet.Total()))
+	lines = append(lines, strings.Repeat("─", labelWidth+cellWidth*8))
+	lines = append(lines, totals)
+	return lines
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// watchersLoadedMsg is sent after an issue's watcher list has been fetched.
+type watchersLoadedMsg struct {
+	issueKey string
+	watchers *jira.WatchersResponse
+	err      error
+}
+
+func (dv *DetailView) loadWatchers(app *App) tea.Cmd {
+	key := dv.issue.Key
+	dv.watchersKey = key
+	return func() tea.Msg {
+		w, err := app.client.GetWatchers(key)
+		return watchersLoadedMsg{issueKey: key, watchers: w, err: err}
+	}
+}
+
+// toggleWatch starts or stops watching the issue, then refreshes the cache.
+func (dv *DetailView) toggleWatch(app *App) tea.Cmd {
+	if app.cfg.AccountID == "" {
+		app.flashMsg = "Run 'shinkansen login' to enable watching"
+		return nil
+	}
+	key := dv.issue.Key
+	accountID := app.cfg.AccountID
+	watching := dv.issue.Fields.Watches != nil && dv.issue.Fields.Watches.IsWatching
+	return func() tea.Msg {
+		var err error
+		text := "Watching " + key
+		if watching {
+			err = app.client.Unwatch(key, accountID)
+			text = "Stopped watching " + key
+		} else {
+			err = app.client.Watch(key, accountID)
+		}
+		if err != nil {
+			return watchDoneMsg{issueKey: key, err: err}
+		}
+		if err := refreshIssue(app, key); err != nil {
+			return watchDoneMsg{issueKey: key, err: err}
+		}
+		return watchDoneMsg{issueKey: key, text: text}
+	}
+}
+
+// toggleVote adds or removes the user's vote. Jira rejects votes on your own
+// issues and on resolved ones; the error is shown as-is.
+func (dv *DetailView) toggleVote(app *App) tea.Cmd {
+	key := dv.issue.Key
+	voted := dv.issue.Fields.Votes != nil && dv.issue.Fields.Votes.HasVoted
+	return func() tea.Msg {
+		var err error
+		text := "Voted for " + key
+		if voted {
+			err = app.client.Unvote(key)
+			text = "Removed vote from " + key
+		} else {
+			err = app.client.Vote(key)
+		}
+		if err != nil {
+			return watchDoneMsg{issueKey: key, err: err}
+		}
+		if err := refreshIssue(app, key); err != nil {
+			return watchDoneMsg{issueKey: key, err: err}
+		}
+		return watchDoneMsg{issueKey: key, text: text}
+	}
+}
+
+// renderWatchers returns the overview lines for watchers and votes.
+func (dv DetailView) renderWatchers() []string {
+	var lines []string
+	if w := dv.issue.Fields.Watches; w != nil {
+		value := fmt.Sprintf("%d", w.WatchCount)
+		if w.IsWatching {
+			value += " (you're watching)"
+		}
+		if len(dv.watchers) > 0 {
+			names := make([]string, len(dv.watchers))
+			for i, u := range dv.watchers {
+				names[i] = u.DisplayName
+			}
+			value += " — " + strings.Join(names, ", ")
+		}
+		lines = append(lines, detailLabelStyle.Render("Watchers:")+" "+detailValueStyle.Render(value))
+	}
+	if v := dv.issue.Fields.Votes; v != nil {
+		value := fmt.Sprintf("%d", v.Votes)
+		if v.HasVoted {
+			value += " (incl. yours)"
+		}
+		lines = append(lines, detailLabelStyle.Render("Votes:")+" "+detailValueStyle.Render(value))
+	}
+	return lines
+}
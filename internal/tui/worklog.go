@@ -0,0 +1,404 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// worklogsLoadedMsg is sent after an issue's worklog entries have been fetched.
+type worklogsLoadedMsg struct {
+	issueKey string
+	worklogs []jira.Worklog
+	err      error
+}
+
+// worklogField identifies which field of the log work form is being edited.
+type worklogField int
+
+const (
+	wlFieldTime worklogField = iota
+	wlFieldStarted
+	wlFieldComment
+	wlFieldAdjust
+	wlFieldEstimate
+	wlFieldCount // sentinel: total number of fields
+)
+
+// startedLayout is how the started time is typed into the form.
+const startedLayout = "2006-01-02 15:04"
+
+// adjustModes are the remaining-estimate strategies Jira supports.
+var adjustModes = []string{"auto", "leave", "new", "manual"}
+
+// WorklogForm handles logging new work and editing existing worklog entries.
+type WorklogForm struct {
+	visible       bool
+	issueKey      string
+	worklogID     string // set when editing an existing entry
+	field         worklogField
+	timeSpent     string
+	started       string
+	startedEdited bool // stop deriving started from the duration once typed
+	comment       string
+	adjustIdx     int
+	estimate      string
+	errMsg        string
+	fromTimer     bool // logs the stopped timer, which is kept until the work is logged or queued
+}
+
+// Show opens an empty form for logging new work on an issue.
+func (wf *WorklogForm) Show(issueKey string) {
+	*wf = WorklogForm{
+		visible:  true,
+		issueKey: issueKey,
+		started:  time.Now().Format(startedLayout),
+	}
+}
+
+// ShowPrefilled opens the form with a duration and start time already filled
+// in, e.g. from a stopped timer or a timesheet cell.
+func (wf *WorklogForm) ShowPrefilled(issueKey, timeSpent string, started time.Time) {
+	wf.Show(issueKey)
+	wf.timeSpent = timeSpent
+	if !started.IsZero() {
+		wf.started = started.Format(startedLayout)
+		wf.startedEdited = true
+	}
+}
+
+// ShowTimer opens the form for the time tracked by the timer on issueKey.
+// Esc discards the timer; submitting removes it once Jira has the worklog.
+func (wf *WorklogForm) ShowTimer(issueKey, timeSpent string, started time.Time) {
+	wf.ShowPrefilled(issueKey, timeSpent, started)
+	wf.fromTimer = true
+}
+
+// ShowEdit opens the form pre-filled with an existing worklog entry.
+func (wf *WorklogForm) ShowEdit(issueKey string, w jira.Worklog) {
+	wf.Show(issueKey)
+	wf.worklogID = w.ID
+	wf.timeSpent = w.TimeSpent
+	wf.started = w.StartedTime().Local().Format(startedLayout)
+	wf.startedEdited = true
+	wf.comment = w.CommentText()
+}
+
+// Hide closes the form.
+func (wf *WorklogForm) Hide() {
+	wf.visible = false
+}
+
+// modes returns the adjust modes valid for this form. Jira does not accept
+// "manual" when updating an existing worklog.
+func (wf *WorklogForm) modes() []string {
+	if wf.worklogID != "" {
+		return adjustModes[:3]
+	}
+	return adjustModes
+}
+
+// Input validates the form and converts it into a worklog request.
+func (wf *WorklogForm) Input() (jira.WorklogInput, error) {
+	var in jira.WorklogInput
+	if _, err := jira.ParseDuration(wf.timeSpent); err != nil {
+		return in, fmt.Errorf("time spent: %v", err)
+	}
+	started, err := time.ParseInLocation(startedLayout, strings.TrimSpace(wf.started), time.Local)
+	if err != nil {
+		return in, fmt.Errorf("started: use YYYY-MM-DD HH:MM")
+	}
+	if started.After(time.Now()) {
+		return in, fmt.Errorf("started is in the future")
+	}
+
+	mode := wf.modes()[wf.adjustIdx]
+	in = jira.WorklogInput{
+		TimeSpent:      strings.TrimSpace(wf.timeSpent),
+		Started:        started,
+		Comment:        wf.comment,
+		AdjustEstimate: mode,
+	}
+	if wf.needsEstimate() {
+		if _, err := jira.ParseDuration(wf.estimate); err != nil {
+			return in, fmt.Errorf("estimate: %v", err)
+		}
+		if mode == "new" {
+			in.NewEstimate = strings.TrimSpace(wf.estimate)
+		} else {
+			in.ReduceBy = strings.TrimSpace(wf.estimate)
+		}
+	}
+	return in, nil
+}
+
+// syncStarted keeps the default start time at "now minus duration" until the
+// user types a start time of their own.
+func (wf *WorklogForm) syncStarted() {
+	if wf.startedEdited {
+		return
+	}
+	started := time.Now()
+	if d, err := jira.ParseDuration(wf.timeSpent); err == nil {
+		started = started.Add(-d)
+	}
+	wf.started = started.Format(startedLayout)
+}
+
+// needsEstimate reports whether the selected adjust mode takes a value.
+func (wf *WorklogForm) needsEstimate() bool {
+	mode := wf.modes()[wf.adjustIdx]
+	return mode == "new" || mode == "manual"
+}
+
+// moveField cycles through the fields, skipping the estimate value when the
+// selected adjust mode doesn't use it.
+func (wf *WorklogForm) moveField(dir int) {
+	wf.field = (wf.field + worklogField(dir) + wlFieldCount) % wlFieldCount
+	if wf.field == wlFieldEstimate && !wf.needsEstimate() {
+		wf.field = (wf.field + worklogField(dir) + wlFieldCount) % wlFieldCount
+	}
+}
+
+func (wf WorklogForm) Update(msg tea.Msg, app *App) (WorklogForm, tea.Cmd) {
+	if !wf.visible {
+		return wf, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if wf.fromTimer {
+				app.discardTimer(wf.issueKey)
+			}
+			wf.Hide()
+			return wf, nil
+
+		case "tab", "down":
+			wf.moveField(1)
+			return wf, nil
+
+		case "shift+tab", "up":
+			wf.moveField(-1)
+			return wf, nil
+
+		case "left":
+			if wf.field == wlFieldAdjust && wf.adjustIdx > 0 {
+				wf.adjustIdx--
+			}
+			return wf, nil
+
+		case "right":
+			if wf.field == wlFieldAdjust && wf.adjustIdx < len(wf.modes())-1 {
+				wf.adjustIdx++
+			}
+			return wf, nil
+
+		case "enter", "ctrl+s":
+			in, err := wf.Input()
+			if err != nil {
+				wf.errMsg = err.Error()
+				return wf, nil
+			}
+			key := wf.issueKey
+			id := wf.worklogID
+			fromTimer := wf.fromTimer
+			wf.Hide()
+			return wf, func() tea.Msg {
+				if id != "" {
+					err := app.client.UpdateWorklog(key, id, in)
+					return logWorkDoneMsg{issueKey: key, err: err}
+				}
+				err := app.client.LogWork(key, in)
+				if jira.IsOffline(err) {
+					if qerr := app.store.QueueWorklog(key, in); qerr == nil {
+						return logWorkDoneMsg{issueKey: key, queued: true, fromTimer: fromTimer}
+					}
+				}
+				return logWorkDoneMsg{issueKey: key, err: err, fromTimer: fromTimer}
+			}
+
+		case "backspace":
+			switch wf.field {
+			case wlFieldTime:
+				if wf.timeSpent != "" {
+					wf.timeSpent = dropLastRune(wf.timeSpent)
+					wf.syncStarted()
+				}
+			case wlFieldStarted:
+				if wf.started != "" {
+					wf.started = dropLastRune(wf.started)
+					wf.startedEdited = true
+				}
+			case wlFieldComment:
+				if wf.comment != "" {
+					wf.comment = dropLastRune(wf.comment)
+				}
+			case wlFieldEstimate:
+				if wf.estimate != "" {
+					wf.estimate = dropLastRune(wf.estimate)
+				}
+			}
+			return wf, nil
+
+		default:
+			ch := msg.String()
+			if len(ch) == 1 || ch == " " {
+				switch wf.field {
+				case wlFieldTime:
+					wf.timeSpent += ch
+					wf.syncStarted()
+				case wlFieldStarted:
+					wf.started += ch
+					wf.startedEdited = true
+				case wlFieldComment:
+					wf.comment += ch
+				case wlFieldEstimate:
+					wf.estimate += ch
+				}
+			}
+			return wf, nil
+		}
+	}
+	return wf, nil
+}
+
+func (wf WorklogForm) View(width, height int) string {
+	if !wf.visible {
+		return ""
+	}
+
+	title := "Log Work on " + wf.issueKey
+	if wf.worklogID != "" {
+		title = "Edit Worklog on " + wf.issueKey
+	}
+
+	var lines []string
+	lines = append(lines, detailHeaderStyle.Render(title))
+
+	label := func(f worklogField, name string) string {
+		if wf.field == f {
+			return searchPromptStyle.Render(fmt.Sprintf("> %-13s", name))
+		}
+		return fmt.Sprintf("  %-13s", name)
+	}
+	value := func(f worklogField, v string) string {
+		if wf.field == f {
+			return v + "█"
+		}
+		return v
+	}
+
+	lines = append(lines, label(wlFieldTime, "Time spent:")+value(wlFieldTime, wf.timeSpent))
+	lines = append(lines, label(wlFieldStarted, "Started:")+value(wlFieldStarted, wf.started))
+	lines = append(lines, label(wlFieldComment, "Comment:")+value(wlFieldComment, wf.comment))
+
+	var modeParts []string
+	for i, m := range wf.modes() {
+		if i == wf.adjustIdx {
+			modeParts = append(modeParts, selectedStyle.Render(" "+m+" "))
+		} else {
+			modeParts = append(modeParts, helpDescStyle.Render(" "+m+" "))
+		}
+	}
+	lines = append(lines, label(wlFieldAdjust, "Estimate:")+strings.Join(modeParts, " "))
+
+	estimateHint := ""
+	switch wf.modes()[wf.adjustIdx] {
+	case "new":
+		estimateHint = "New remaining:"
+	case "manual":
+		estimateHint = "Reduce by:"
+	}
+	if estimateHint != "" {
+		lines = append(lines, label(wlFieldEstimate, estimateHint)+value(wlFieldEstimate, wf.estimate))
+	}
+
+	if wf.errMsg != "" {
+		lines = append(lines, "")
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#cc3333")).Bold(true).Render("  "+wf.errMsg))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, helpDescStyle.Render("  Durations: 1w 2d 3h 30m  Tab: next field  Left/Right: estimate mode"))
+	lines = append(lines, helpDescStyle.Render("  Enter: save  Esc: cancel"))
+
+	content := strings.Join(lines, "\n")
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		panelStyle.Width(min(width-4, 80)).Render(content),
+	)
+}
+
+// loadWorklogs fetches the worklog entries of an issue for the worklog tab.
+func (dv *DetailView) loadWorklogs(app *App) tea.Cmd {
+	key := dv.issue.Key
+	dv.worklogKey = key
+	dv.worklogLoading = true
+	return func() tea.Msg {
+		worklogs, err := app.client.GetWorklogs(key)
+		return worklogsLoadedMsg{issueKey: key, worklogs: worklogs, err: err}
+	}
+}
+
+// selectedWorklog returns the worklog under the cursor in the worklog tab.
+func (dv *DetailView) selectedWorklog() *jira.Worklog {
+	if dv.worklogCursor < 0 || dv.worklogCursor >= len(dv.worklogs) {
+		return nil
+	}
+	return &dv.worklogs[dv.worklogCursor]
+}
+
+func (dv DetailView) renderWorklogs(width int, accountID string) []string {
+	var lines []string
+	if dv.worklogLoading && len(dv.worklogs) == 0 {
+		return append(lines, helpDescStyle.Render("Loading worklogs..."))
+	}
+	if len(dv.worklogs) == 0 {
+		return append(lines, helpDescStyle.Render("No work logged"))
+	}
+
+	var total int
+	for _, w := range dv.worklogs {
+		total += w.TimeSpentSeconds
+	}
+	lines = append(lines, detailLabelStyle.Render("Total:")+" "+detailValueStyle.Render(jira.FormatDuration(time.Duration(total)*time.Second)))
+	lines = append(lines, "")
+
+	for i, w := range dv.worklogs {
+		own := " "
+		if accountID != "" && w.Author.AccountID == accountID {
+			own = "*"
+		}
+		line := fmt.Sprintf("%s %s  %-8s %-20s %s",
+			own,
+			w.StartedTime().Local().Format(startedLayout),
+			w.TimeSpent,
+			truncate(w.Author.DisplayName, 20),
+			strings.ReplaceAll(w.CommentText(), "\n", " "),
+		)
+		line = truncate(line, width-6)
+		if i == dv.worklogCursor {
+			line = selectedStyle.Width(width - 6).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if dv.confirmDelete {
+		lines = append(lines, "")
+		lines = append(lines, searchPromptStyle.Render("Delete this worklog? (y/n)"))
+	}
+	return lines
+}
+
+// dropLastRune removes the last character of s, for backspace, keeping
+// multi-byte runes whole.
+func dropLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
@@ -0,0 +1,147 @@
+// Package webhook receives Jira webhooks and applies them to the cache right
+// away, so a reachable shinkansen (through a tunnel, or next to a
+// self-hosted Jira) doesn't wait for the next poll.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/temujinlabs/shinkansen/internal/cache"
+	"github.com/temujinlabs/shinkansen/internal/config"
+	"github.com/temujinlabs/shinkansen/internal/jira"
+)
+
+// maxBody caps the size of a webhook payload.
+const maxBody = 10 << 20
+
+// payload is the part of a Jira webhook body needed to find the issue.
+type payload struct {
+	WebhookEvent string `json:"webhookEvent"`
+	Issue        *struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"issue"`
+	Worklog *struct {
+		IssueID string `json:"issueId"`
+	} `json:"worklog"`
+}
+
+// Handler applies webhooks to the cache and reports each change to
+// OnChange.
+type Handler struct {
+	client   *jira.Client
+	store    *cache.Store
+	scopes   []config.SyncScope
+	secret   string
+	OnChange func(keys []string, events []cache.Event)
+}
+
+// NewHandler returns a handler that only accepts requests carrying secret:
+// as the HMAC-SHA256 X-Hub-Signature Jira sends when the webhook has a
+// secret, or as a ?secret= query parameter for Jira versions without one.
+// Refreshed issues are tagged with the scopes that include them.
+func NewHandler(client *jira.Client, store *cache.Store, scopes []config.SyncScope, secret string) *Handler {
+	return &Handler{client: client, store: store, scopes: scopes, secret: secret}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r, body) {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+
+	keys, events, err := h.apply(&p)
+	if err != nil {
+		log.Printf("webhook %s: %v", p.WebhookEvent, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if len(keys) > 0 {
+		log.Printf("webhook %s: %s", p.WebhookEvent, strings.Join(keys, ", "))
+		if h.OnChange != nil {
+			h.OnChange(keys, events)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the request's signature or secret parameter.
+func (h *Handler) verify(r *http.Request, body []byte) bool {
+	if sig := r.Header.Get("X-Hub-Signature"); sig != "" {
+		method, value, ok := strings.Cut(sig, "=")
+		if !ok || method != "sha256" {
+			return false
+		}
+		got, err := hex.DecodeString(value)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(h.secret))
+		mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+	given := r.URL.Query().Get("secret")
+	return given != "" && subtle.ConstantTimeCompare([]byte(given), []byte(h.secret)) == 1
+}
+
+// apply updates the cache for one webhook and returns the affected keys.
+// Unknown webhook events are ignored.
+func (h *Handler) apply(p *payload) ([]string, []cache.Event, error) {
+	switch p.WebhookEvent {
+	case "jira:issue_deleted":
+		if p.Issue == nil {
+			return nil, nil, nil
+		}
+		return []string{p.Issue.Key}, nil, h.store.DeleteIssue(p.Issue.Key)
+
+	case "jira:issue_created", "jira:issue_updated", "comment_created", "comment_updated", "comment_deleted":
+		if p.Issue == nil {
+			return nil, nil, nil
+		}
+		return h.refresh(p.Issue.Key)
+
+	case "worklog_created", "worklog_updated", "worklog_deleted":
+		if p.Worklog == nil {
+			return nil, nil, nil
+		}
+		return h.refresh(p.Worklog.IssueID)
+	}
+	return nil, nil, nil
+}
+
+// refresh re-fetches an issue, since webhook bodies use the v2 format, and
+// tags it with its scopes so it shows in (or leaves) scoped views before the
+// next reconcile.
+func (h *Handler) refresh(keyOrID string) ([]string, []cache.Event, error) {
+	issue, events, err := cache.RefreshIssue(h.client, h.store, keyOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cache.TagScopes(h.client, h.store, issue, h.scopes); err != nil {
+		// The issue is cached; reconciling fixes its tags
+		log.Printf("webhook: scopes of %s: %v", issue.Key, err)
+	}
+	return []string{issue.Key}, events, nil
+}